@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/cmdexec"
+	"github.com/suzuki-shunsuke/rgo/pkg/controller/run"
+	"github.com/suzuki-shunsuke/rgo/pkg/github"
+	"github.com/suzuki-shunsuke/slog-util/slogutil"
+	"github.com/urfave/cli/v3"
+)
+
+type DoctorArgs struct {
+	Config    string
+	RgoConfig string
+	Repo      string
+	Publish   []string
+}
+
+func doctorCommand(logger *slogutil.Logger) *cli.Command {
+	args := &DoctorArgs{}
+	return &cli.Command{
+		Name:  "doctor",
+		Usage: "Check if the local environment is ready to release",
+		Description: `Check git, GitHub CLI, the GitHub token, push access to repositories, and the local checkout, and print a table of results with hints.
+The same checks run at the start of rgo run.
+
+e.g.
+
+$ rgo doctor`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				Aliases:     []string{"c"},
+				Usage:       "Configuration file path (.goreleaser.yaml)",
+				Destination: &args.Config,
+			},
+			&cli.StringFlag{
+				Name:        "rgo-config",
+				Usage:       "rgo's configuration file path (.rgo.yaml)",
+				Destination: &args.RgoConfig,
+			},
+			&cli.StringFlag{
+				Name:        "repo",
+				Aliases:     []string{"R"},
+				Usage:       "Released repository (<owner>/<name>). By default, it's parsed from the URL of the remote origin",
+				Destination: &args.Repo,
+			},
+			&cli.StringSliceFlag{
+				Name:        "publish",
+				Aliases:     []string{"p"},
+				Usage:       "Publishers to check (homebrew, scoop, winget, aur, nix, krew, git_files)",
+				Destination: &args.Publish,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return doctorAction(ctx, logger, cmd, args)
+		},
+	}
+}
+
+func doctorAction(ctx context.Context, logger *slogutil.Logger, cmd *cli.Command, args *DoctorArgs) error {
+	param := &run.ParamRun{
+		ConfigFilePath:    args.Config,
+		RgoConfigFilePath: args.RgoConfig,
+		Stdout:            cmd.Writer,
+		Stderr:            cmd.ErrWriter,
+		Publish:           args.Publish,
+		Repository:        args.Repo,
+	}
+	exec := &cmdexec.Executor{
+		Stderr: cmd.ErrWriter,
+	}
+	ghClient, err := github.New(ctx)
+	if err != nil {
+		return fmt.Errorf("create a GitHub client: %w", err)
+	}
+	ctrl := run.New(afero.NewOsFs(), param, exec, &run.GitHub{
+		Repositories: ghClient.Repositories,
+	})
+	if err := ctrl.Doctor(ctx, logger.Logger); err != nil {
+		return fmt.Errorf("check the environment: %w", err)
+	}
+	return nil
+}
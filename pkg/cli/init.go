@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/controller/initcmd"
+	"github.com/suzuki-shunsuke/slog-util/slogutil"
+	"github.com/urfave/cli/v3"
+)
+
+type InitArgs struct {
+	Config    string
+	RgoConfig string
+	Workflow  string
+	Write     bool
+}
+
+func initCommand(logger *slogutil.Logger) *cli.Command {
+	args := &InitArgs{}
+	return &cli.Command{
+		Name:  "init",
+		Usage: "Set up .goreleaser.yaml and the release workflow for rgo",
+		Description: `Set skip_upload: true to publishers in .goreleaser.yaml and add steps uploading files to GitHub Actions Artifacts to the release workflow.
+Changes are only printed by default. Comments are kept, but the indentation of YAML files is normalized.
+
+e.g.
+
+$ rgo init
+$ rgo init --write`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				Aliases:     []string{"c"},
+				Usage:       "Configuration file path (.goreleaser.yaml)",
+				Destination: &args.Config,
+			},
+			&cli.StringFlag{
+				Name:        "rgo-config",
+				Usage:       "rgo's configuration file path (.rgo.yaml)",
+				Destination: &args.RgoConfig,
+			},
+			&cli.StringFlag{
+				Name:        "workflow",
+				Aliases:     []string{"w"},
+				Usage:       "GitHub Actions workflow file name or path",
+				Value:       "release.yaml",
+				Destination: &args.Workflow,
+			},
+			&cli.BoolFlag{
+				Name:        "write",
+				Usage:       "Write changes to files",
+				Destination: &args.Write,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			ctrl := initcmd.New(afero.NewOsFs(), &initcmd.Param{
+				ConfigFilePath:    args.Config,
+				RgoConfigFilePath: args.RgoConfig,
+				Workflow:          args.Workflow,
+				Write:             args.Write,
+				Stdout:            cmd.Writer,
+			})
+			if err := ctrl.Init(ctx, logger.Logger); err != nil {
+				return fmt.Errorf("initialize: %w", err)
+			}
+			return nil
+		},
+	}
+}
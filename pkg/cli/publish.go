@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/cmdexec"
+	"github.com/suzuki-shunsuke/rgo/pkg/controller/run"
+	"github.com/suzuki-shunsuke/rgo/pkg/github"
+	"github.com/suzuki-shunsuke/slog-util/slogutil"
+	"github.com/urfave/cli/v3"
+)
+
+type PublishArgs struct {
+	Config        string
+	RgoConfig     string
+	Dist          string
+	ArtifactPaths []string
+	Version       string
+	Publish       []string
+	DryRun        bool
+	AutoMerge     bool
+	Web           bool
+	Prerelease    []string
+	Parallel      int
+	KeepGoing     bool
+}
+
+func publishCommand(logger *slogutil.Logger) *cli.Command {
+	args := &PublishArgs{}
+	return &cli.Command{
+		Name:  "publish",
+		Usage: "Publish packages from a local GoReleaser dist directory",
+		Description: `Push files in a local GoReleaser dist directory to repositories without creating a tag and waiting for GitHub Actions.
+
+e.g.
+
+$ rgo publish --dist ./dist v1.2.3`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				Aliases:     []string{"c"},
+				Usage:       "Configuration file path (.goreleaser.yaml)",
+				Destination: &args.Config,
+			},
+			&cli.StringFlag{
+				Name:        "rgo-config",
+				Usage:       "rgo's configuration file path (.rgo.yaml)",
+				Destination: &args.RgoConfig,
+			},
+			&cli.StringSliceFlag{
+				Name:        "artifact-path",
+				Usage:       "Path of files for a publisher in the dist directory (<publisher>=<path>). e.g. homebrew=homebrew",
+				Destination: &args.ArtifactPaths,
+			},
+			&cli.StringFlag{
+				Name:        "dist",
+				Aliases:     []string{"artifacts-dir"},
+				Usage:       "Directory laid out like GoReleaser's dist directory (homebrew, scoop, winget, aur, nix, and krew)",
+				Required:    true,
+				Destination: &args.Dist,
+			},
+			&cli.StringSliceFlag{
+				Name:        "publish",
+				Aliases:     []string{"p"},
+				Usage:       "Publishers to process (homebrew, scoop, winget, aur, nix, krew, git_files)",
+				Destination: &args.Publish,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "Print commands, commit messages, and changes without pushing commits or creating pull requests",
+				Destination: &args.DryRun,
+			},
+			&cli.BoolFlag{
+				Name:        "auto-merge",
+				Usage:       "Enable auto-merge of created pull requests",
+				Destination: &args.AutoMerge,
+			},
+			&cli.IntFlag{
+				Name:        "parallel",
+				Usage:       "Maximum number of publishers processed concurrently",
+				Value:       1,
+				Destination: &args.Parallel,
+			},
+			&cli.BoolFlag{
+				Name:        "keep-going",
+				Usage:       "Attempt all publishers and repositories even if some of them fail, and exit with non-zero if any of them failed",
+				Destination: &args.KeepGoing,
+			},
+			&cli.StringSliceFlag{
+				Name:        "prerelease",
+				Usage:       "Override the policy of prereleases ([<publisher>=]<skip|publish|alternate[:<owner>/<name>]>). e.g. winget=publish",
+				Destination: &args.Prerelease,
+			},
+			&cli.BoolFlag{
+				Name:        "web",
+				Usage:       "Open pages to create pull requests in a web browser instead of creating them via GitHub API",
+				Destination: &args.Web,
+			},
+		},
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:        "version",
+				Destination: &args.Version,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return publishAction(ctx, logger, cmd, args)
+		},
+	}
+}
+
+func publishAction(ctx context.Context, logger *slogutil.Logger, cmd *cli.Command, args *PublishArgs) error {
+	if args.Version == "" {
+		return errors.New("version argument is required")
+	}
+	param := &run.ParamRun{
+		ConfigFilePath:    args.Config,
+		RgoConfigFilePath: args.RgoConfig,
+		ArtifactPaths:     args.ArtifactPaths,
+		Stdout:            cmd.Writer,
+		Stderr:            cmd.ErrWriter,
+		Version:           args.Version,
+		Publish:           args.Publish,
+		DryRun:            args.DryRun,
+		DistDir:           args.Dist,
+		AutoMerge:         args.AutoMerge,
+		Web:               args.Web,
+		Prerelease:        args.Prerelease,
+		Parallel:          args.Parallel,
+		KeepGoing:         args.KeepGoing,
+	}
+	exec := &cmdexec.Executor{
+		Stdout: cmd.Writer,
+		Stderr: cmd.ErrWriter,
+	}
+	ghClient, err := github.New(ctx)
+	if err != nil {
+		return fmt.Errorf("create a GitHub client: %w", err)
+	}
+	ctrl := run.New(afero.NewOsFs(), param, exec, &run.GitHub{
+		Repositories: ghClient.Repositories,
+		Actions:      ghClient.Actions,
+		PullRequests: ghClient.PullRequests,
+		Git:          ghClient.Git,
+	})
+	if err := ctrl.Publish(ctx, logger.Logger); err != nil {
+		return fmt.Errorf("publish packages: %w", err)
+	}
+	return nil
+}
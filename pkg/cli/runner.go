@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/suzuki-shunsuke/rgo/pkg/cmdexec"
@@ -15,11 +16,29 @@ import (
 )
 
 type RunArgs struct {
-	Config   string
-	Workflow string
-	RunID    string
-	Version  string
-	Publish  []string
+	Config    string
+	RgoConfig string
+	Workflow  string
+
+	ArtifactName  string
+	ArtifactPaths []string
+	RunID         string
+	Version       string
+	Publish       []string
+	DryRun        bool
+	Resume        bool
+	StateDir      string
+	Repo          string
+	AutoMerge     bool
+	Web           bool
+	Force         []string
+	Prerelease    []string
+	Parallel      int
+	KeepGoing     bool
+	ReportFile    string
+	Output        string
+
+	RunDiscoveryTimeout time.Duration
 }
 
 func Run(ctx context.Context, logger *slogutil.Logger, env *urfave.Env) error {
@@ -39,6 +58,21 @@ func Run(ctx context.Context, logger *slogutil.Logger, env *urfave.Env) error {
 						Usage:       "Configuration file path (.goreleaser.yaml)",
 						Destination: &runArgs.Config,
 					},
+					&cli.StringFlag{
+						Name:        "rgo-config",
+						Usage:       "rgo's configuration file path (.rgo.yaml)",
+						Destination: &runArgs.RgoConfig,
+					},
+					&cli.StringFlag{
+						Name:        "artifact-name",
+						Usage:       "Default name of the GitHub Actions Artifact (default: goreleaser)",
+						Destination: &runArgs.ArtifactName,
+					},
+					&cli.StringSliceFlag{
+						Name:        "artifact-path",
+						Usage:       "Artifact and path of files for a publisher (<publisher>=[<artifact name>:]<path>). e.g. homebrew=dist-packages:homebrew",
+						Destination: &runArgs.ArtifactPaths,
+					},
 					&cli.StringFlag{
 						Name:        "workflow",
 						Aliases:     []string{"w"},
@@ -46,6 +80,18 @@ func Run(ctx context.Context, logger *slogutil.Logger, env *urfave.Env) error {
 						Value:       "release.yaml",
 						Destination: &runArgs.Workflow,
 					},
+					&cli.StringFlag{
+						Name:        "repo",
+						Aliases:     []string{"R"},
+						Usage:       "Released repository (<owner>/<name>). By default, it's parsed from the URL of the remote origin",
+						Destination: &runArgs.Repo,
+					},
+					&cli.DurationFlag{
+						Name:        "run-discovery-timeout",
+						Usage:       "How long to look for the workflow run triggered by the tag",
+						Value:       5 * time.Minute, //nolint:mnd
+						Destination: &runArgs.RunDiscoveryTimeout,
+					},
 					&cli.StringFlag{
 						Name:        "run-id",
 						Usage:       "GitHub Actions run ID (skip tag creation if provided)",
@@ -54,9 +100,67 @@ func Run(ctx context.Context, logger *slogutil.Logger, env *urfave.Env) error {
 					&cli.StringSliceFlag{
 						Name:        "publish",
 						Aliases:     []string{"p"},
-						Usage:       "Publishers to process (homebrew, scoop, winget)",
+						Usage:       "Publishers to process (homebrew, scoop, winget, aur, nix, krew, git_files)",
 						Destination: &runArgs.Publish,
 					},
+					&cli.BoolFlag{
+						Name:        "dry-run",
+						Usage:       "Print commands, commit messages, and changes without creating tags, pushing commits, or creating pull requests",
+						Destination: &runArgs.DryRun,
+					},
+					&cli.BoolFlag{
+						Name:        "resume",
+						Usage:       "Resume a failed release from the first unfinished phase",
+						Destination: &runArgs.Resume,
+					},
+					&cli.StringFlag{
+						Name:        "state-dir",
+						Usage:       "Directory where state files of releases are stored (default: .git/rgo)",
+						Destination: &runArgs.StateDir,
+					},
+					&cli.BoolFlag{
+						Name:        "auto-merge",
+						Usage:       "Enable auto-merge of created pull requests",
+						Destination: &runArgs.AutoMerge,
+					},
+					&cli.BoolFlag{
+						Name:        "web",
+						Usage:       "Open pages to create pull requests in a web browser instead of creating them via GitHub API",
+						Destination: &runArgs.Web,
+					},
+					&cli.IntFlag{
+						Name:        "parallel",
+						Usage:       "Maximum number of publishers processed concurrently",
+						Value:       1,
+						Destination: &runArgs.Parallel,
+					},
+					&cli.BoolFlag{
+						Name:        "keep-going",
+						Usage:       "Attempt all publishers and repositories even if some of them fail, and exit with non-zero if any of them failed",
+						Destination: &runArgs.KeepGoing,
+					},
+					&cli.StringFlag{
+						Name:        "report-file",
+						Usage:       "Write a JSON report of the release to the file",
+						Destination: &runArgs.ReportFile,
+					},
+					&cli.StringFlag{
+						Name:        "output",
+						Aliases:     []string{"o"},
+						Usage:       "Output format (text or json). With json, the report is output to stdout and other outputs are written to stderr",
+						Value:       "text",
+						Destination: &runArgs.Output,
+					},
+					&cli.StringSliceFlag{
+						Name:        "prerelease",
+						Usage:       "Override the policy of prereleases ([<publisher>=]<skip|publish|alternate[:<owner>/<name>]>). e.g. winget=publish",
+						Destination: &runArgs.Prerelease,
+					},
+					&cli.StringSliceFlag{
+						Name:        "force",
+						Usage:       "Skip checks before creating a tag (dirty, unpushed, version, version-order, existing-tag, or all)",
+						Destination: &runArgs.Force,
+					},
 				},
 				Arguments: []cli.Argument{
 					&cli.StringArg{
@@ -68,6 +172,10 @@ func Run(ctx context.Context, logger *slogutil.Logger, env *urfave.Env) error {
 					return runAction(ctx, logger, cmd, runArgs)
 				},
 			},
+			publishCommand(logger),
+			initCommand(logger),
+			validateCommand(logger),
+			doctorCommand(logger),
 		},
 	}).Run(ctx, env.Args)
 }
@@ -77,22 +185,49 @@ func runAction(ctx context.Context, logger *slogutil.Logger, cmd *cli.Command, a
 		return errors.New("version argument is required")
 	}
 	param := &run.ParamRun{
-		ConfigFilePath: args.Config,
-		Stderr:         cmd.ErrWriter,
-		Version:        args.Version,
-		RunID:          args.RunID,
-		Workflow:       args.Workflow,
-		Publish:        args.Publish,
+		ConfigFilePath:    args.Config,
+		RgoConfigFilePath: args.RgoConfig,
+		ArtifactName:      args.ArtifactName,
+		ArtifactPaths:     args.ArtifactPaths,
+		Stdout:            cmd.Writer,
+		Stderr:            cmd.ErrWriter,
+		Version:           args.Version,
+		RunID:             args.RunID,
+		Workflow:          args.Workflow,
+		Publish:           args.Publish,
+		DryRun:            args.DryRun,
+		Resume:            args.Resume,
+		StateDir:          args.StateDir,
+		Repository:        args.Repo,
+		AutoMerge:         args.AutoMerge,
+		Web:               args.Web,
+		Force:             args.Force,
+		Prerelease:        args.Prerelease,
+		Parallel:          args.Parallel,
+		KeepGoing:         args.KeepGoing,
+		ReportFile:        args.ReportFile,
+		Output:            args.Output,
+
+		RunDiscoveryTimeout: args.RunDiscoveryTimeout,
 	}
 	exec := &cmdexec.Executor{
 		Stdout: cmd.Writer,
 		Stderr: cmd.ErrWriter,
 	}
+	if args.Output == "json" {
+		// Keep stdout for the report.
+		exec.Stdout = cmd.ErrWriter
+	}
 	ghClient, err := github.New(ctx)
 	if err != nil {
 		return fmt.Errorf("create a GitHub client: %w", err)
 	}
-	ctrl := run.New(afero.NewOsFs(), param, exec, ghClient.Repositories)
+	ctrl := run.New(afero.NewOsFs(), param, exec, &run.GitHub{
+		Repositories: ghClient.Repositories,
+		Actions:      ghClient.Actions,
+		PullRequests: ghClient.PullRequests,
+		Git:          ghClient.Git,
+	})
 	if err := ctrl.Run(ctx, logger.Logger); err != nil {
 		return fmt.Errorf("run release: %w", err)
 	}
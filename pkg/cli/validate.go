@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/controller/validate"
+	"github.com/suzuki-shunsuke/slog-util/slogutil"
+	"github.com/urfave/cli/v3"
+)
+
+type ValidateArgs struct {
+	Config    string
+	RgoConfig string
+	Workflow  string
+	Output    string
+}
+
+func validateCommand(logger *slogutil.Logger) *cli.Command {
+	args := &ValidateArgs{}
+	return &cli.Command{
+		Name:  "validate",
+		Usage: "Validate .goreleaser.yaml and the release workflow",
+		Description: `Check fields publishers require, skip_upload, and the step uploading files to GitHub Actions Artifacts before releasing.
+Problems are output with file and line positions.
+
+e.g.
+
+$ rgo validate
+.goreleaser.yaml:12:5: brews[0]: repository.owner is required
+$ rgo validate --output json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				Aliases:     []string{"c"},
+				Usage:       "Configuration file path (.goreleaser.yaml)",
+				Destination: &args.Config,
+			},
+			&cli.StringFlag{
+				Name:        "rgo-config",
+				Usage:       "rgo's configuration file path (.rgo.yaml)",
+				Destination: &args.RgoConfig,
+			},
+			&cli.StringFlag{
+				Name:        "workflow",
+				Aliases:     []string{"w"},
+				Usage:       "GitHub Actions workflow file name or path",
+				Value:       "release.yaml",
+				Destination: &args.Workflow,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "Output format (text or json)",
+				Value:       "text",
+				Destination: &args.Output,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			ctrl := validate.New(afero.NewOsFs(), &validate.Param{
+				ConfigFilePath:    args.Config,
+				RgoConfigFilePath: args.RgoConfig,
+				Workflow:          args.Workflow,
+				Output:            args.Output,
+				Stdout:            cmd.Writer,
+			})
+			if err := ctrl.Validate(ctx, logger.Logger); err != nil {
+				return fmt.Errorf("validate: %w", err)
+			}
+			return nil
+		},
+	}
+}
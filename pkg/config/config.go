@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v3"
@@ -15,32 +16,90 @@ type Config struct {
 	Brews         []Brew         `yaml:"brews"`
 	Scoops        []Scoop        `yaml:"scoops"`
 	Winget        []Winget       `yaml:"winget"`
+	AURs          []AUR          `yaml:"aurs"`
+	Nix           []Nix          `yaml:"nix"`
+	Krews         []Krew         `yaml:"krews"`
+	// Rgo is read from .rgo.yaml, not .goreleaser.yaml.
+	Rgo Rgo `yaml:"-"`
 }
 
 type HomebrewCask struct {
-	Repository Repository `yaml:"repository"`
+	Repository        Repository   `yaml:"repository"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
 }
 
 type Brew struct {
-	Repository Repository `yaml:"repository"`
+	Repository        Repository   `yaml:"repository"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
 }
 
 type Scoop struct {
-	Repository Repository `yaml:"repository"`
+	Repository        Repository   `yaml:"repository"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
 }
 
 type Winget struct {
-	Publisher  string     `yaml:"publisher"`
-	Repository WingetRepo `yaml:"repository"`
+	Publisher         string       `yaml:"publisher"`
+	Repository        Repository   `yaml:"repository"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
 }
 
-type Repository struct {
-	Owner  string `yaml:"owner"`
-	Name   string `yaml:"name"`
-	Branch string `yaml:"branch"`
+// AUR is a package of Arch User Repository.
+type AUR struct {
+	// Name is the package name. The default is <project name>-bin.
+	Name string `yaml:"name"`
+	// GitURL is the SSH URL of the AUR git repository. e.g. ssh://aur@aur.archlinux.org/foo-bin.git
+	GitURL string `yaml:"git_url"`
+	// Directory is the directory in the repository where files are pushed.
+	Directory         string       `yaml:"directory"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
+}
+
+// Nix is a Nix derivation pushed to a NUR repository.
+type Nix struct {
+	// Name is the derivation name. The default is the project name.
+	Name       string     `yaml:"name"`
+	Repository Repository `yaml:"repository"`
+	// Path is the path of the .nix file in the repository. The default is pkgs/<name>/default.nix.
+	Path              string       `yaml:"path"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
+}
+
+// Krew is a kubectl plugin manifest pushed to a krew index.
+type Krew struct {
+	// Name is the plugin name. The default is the project name.
+	Name              string       `yaml:"name"`
+	Repository        Repository   `yaml:"repository"`
+	CommitMsgTemplate string       `yaml:"commit_msg_template"`
+	CommitAuthor      CommitAuthor `yaml:"commit_author"`
+}
+
+// CommitAuthor is the author of commits pushed to repositories.
+// If it's empty, the local git configuration is used.
+type CommitAuthor struct {
+	Name    string        `yaml:"name"`
+	Email   string        `yaml:"email"`
+	Signing CommitSigning `yaml:"signing"`
+}
+
+// CommitSigning is the configuration to sign commits with GPG, X.509, or SSH.
+type CommitSigning struct {
+	Enabled bool `yaml:"enabled"`
+	// Key is the signing key. If it's empty, git's user.signingkey is used.
+	Key string `yaml:"key"`
+	// Program is the program to sign commits. e.g. gpg, ssh-keygen
+	Program string `yaml:"program"`
+	// Format is openpgp, x509, or ssh. The default is openpgp.
+	Format string `yaml:"format"`
 }
 
-type WingetRepo struct {
+type Repository struct {
 	Owner       string      `yaml:"owner"`
 	Name        string      `yaml:"name"`
 	Branch      string      `yaml:"branch"`
@@ -48,23 +107,62 @@ type WingetRepo struct {
 }
 
 type PullRequest struct {
-	Enabled bool       `yaml:"enabled"`
-	Draft   bool       `yaml:"draft"`
-	Base    Repository `yaml:"base"`
+	Enabled bool            `yaml:"enabled"`
+	Draft   bool            `yaml:"draft"`
+	Base    PullRequestBase `yaml:"base"`
+}
+
+type PullRequestBase struct {
+	Owner  string `yaml:"owner"`
+	Name   string `yaml:"name"`
+	Branch string `yaml:"branch"`
+}
+
+// PublisherKeys maps keys of .goreleaser.yaml to rgo's publishers.
+var PublisherKeys = []struct { //nolint:gochecknoglobals
+	Key       string
+	Publisher string
+}{
+	{"brews", "homebrew"},
+	{"homebrew_casks", "homebrew"},
+	{"scoops", "scoop"},
+	{"winget", "winget"},
+	{"aurs", "aur"},
+	{"nix", "nix"},
+	{"krews", "krew"},
 }
 
 func Read(fs afero.Fs, cfgFilePath string) (*Config, error) {
+	p, err := FilePath(fs, cfgFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return readFile(fs, p)
+}
+
+// FilePath returns the path of the configuration file.
+// If cfgFilePath is empty, .goreleaser.yaml or .goreleaser.yml is returned.
+func FilePath(fs afero.Fs, cfgFilePath string) (string, error) {
 	if cfgFilePath != "" {
-		return readFile(fs, cfgFilePath)
+		return cfgFilePath, nil
 	}
-	cfg, err := readFile(fs, ".goreleaser.yaml")
-	if err == nil {
-		return cfg, nil
+	for _, p := range []string{".goreleaser.yaml", ".goreleaser.yml"} {
+		if _, err := fs.Stat(p); err == nil {
+			return p, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("check if %s exists: %w", p, err)
+		}
 	}
-	if !errors.Is(err, os.ErrNotExist) {
-		return nil, err
+	return "", fmt.Errorf("open a config file: .goreleaser.yaml: %w", os.ErrNotExist)
+}
+
+// WorkflowFilePath returns the path of the release workflow file.
+// If workflow is a file name, it's in .github/workflows.
+func WorkflowFilePath(workflow string) string {
+	if filepath.Base(workflow) == workflow {
+		return filepath.Join(".github", "workflows", workflow)
 	}
-	return readFile(fs, ".goreleaser.yml")
+	return workflow
 }
 
 func readFile(fs afero.Fs, p string) (*Config, error) {
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// Rgo is the configuration specific to rgo.
+// GoReleaser rejects unknown fields in .goreleaser.yaml, so it's read from a separate file .rgo.yaml.
+type Rgo struct {
+	Artifacts   Artifacts      `yaml:"artifacts"`
+	PullRequest RgoPullRequest `yaml:"pull_request"`
+	Commit      RgoCommit      `yaml:"commit"`
+	GitFiles    []GitFiles     `yaml:"git_files"`
+	Prerelease  Prerelease     `yaml:"prerelease"`
+}
+
+// Prerelease is the configuration of what happens to prereleases such as v1.0.0-rc.1.
+type Prerelease struct {
+	// Policy is the default policy of publishers (skip or publish). The default is skip.
+	Policy string `yaml:"policy"`
+	// Publishers are policies for each publisher.
+	Publishers map[string]PrereleasePolicy `yaml:"publishers"`
+}
+
+type PrereleasePolicy struct {
+	// Policy is skip, publish, or alternate.
+	// alternate publishes prereleases to Repository instead of the repository in .goreleaser.yaml.
+	Policy string `yaml:"policy"`
+	// Repository is the alternate repository. e.g. homebrew-tap-beta
+	Repository Repository `yaml:"repository"`
+}
+
+const (
+	PrereleaseSkip      = "skip"
+	PrereleasePublish   = "publish"
+	PrereleaseAlternate = "alternate"
+)
+
+// Get returns the policy of a publisher, filling in defaults.
+func (p *Prerelease) Get(publisher string) PrereleasePolicy {
+	policy := p.Publishers[publisher]
+	if policy.Policy == "" {
+		policy.Policy = p.Policy
+	}
+	if policy.Policy == "" {
+		policy.Policy = PrereleaseSkip
+	}
+	return policy
+}
+
+func (p *Prerelease) Set(publisher string, policy PrereleasePolicy) {
+	if p.Publishers == nil {
+		p.Publishers = map[string]PrereleasePolicy{}
+	}
+	p.Publishers[publisher] = policy
+}
+
+// GitFiles pushes arbitrary files in an artifact to a repository.
+// e.g. shell completions, version.json, and install scripts.
+type GitFiles struct {
+	// Artifact is the artifact name. If it's empty, Artifacts.Name is used.
+	Artifact string `yaml:"artifact"`
+	// ArtifactGlob is a glob of files in the artifact. Matched directories are copied recursively.
+	ArtifactGlob string `yaml:"artifact_glob"`
+	// Repository is <owner>/<name>.
+	Repository string `yaml:"repository"`
+	Branch     string `yaml:"branch"`
+	// Path is the directory in the repository where files are copied. The default is the root directory.
+	Path          string      `yaml:"path"`
+	CommitMessage string      `yaml:"commit_message"`
+	PullRequest   PullRequest `yaml:"pull_request"`
+}
+
+// ArtifactName returns the artifact name of the files.
+func (g *GitFiles) ArtifactName(artifacts *Artifacts) string {
+	if g.Artifact != "" {
+		return g.Artifact
+	}
+	if artifacts.Name != "" {
+		return artifacts.Name
+	}
+	return DefaultArtifactName
+}
+
+// RgoCommit is the configuration of how commits are created and pushed.
+type RgoCommit struct {
+	// Backend is git or api. The default is git.
+	// git clones repositories and pushes commits with git.
+	// api creates commits via GitHub Git Data API, and GitHub marks them as verified.
+	Backend string `yaml:"backend"`
+}
+
+const (
+	CommitBackendGit = "git"
+	CommitBackendAPI = "api"
+)
+
+// RgoPullRequest is the configuration of pull requests which GoReleaser doesn't support.
+type RgoPullRequest struct {
+	// AutoMerge enables auto-merge of pull requests.
+	AutoMerge bool `yaml:"auto_merge"`
+	// MergeMethod is the merge method of auto-merge (merge, squash, or rebase). The default is squash.
+	MergeMethod string `yaml:"merge_method"`
+}
+
+// DefaultArtifactName is the name of the GitHub Actions Artifact including files built by GoReleaser.
+const DefaultArtifactName = "goreleaser"
+
+// defaultArtifactPaths are paths of files for each publisher in an artifact.
+// They are same as GoReleaser's dist directory.
+var defaultArtifactPaths = map[string]string{ //nolint:gochecknoglobals
+	"homebrew": "homebrew",
+	"scoop":    "scoop",
+	"winget":   "winget/manifests",
+	"aur":      "aur",
+	"nix":      "nix",
+	"krew":     "krew",
+}
+
+type Artifacts struct {
+	// Name is the default artifact name.
+	Name string `yaml:"name"`
+	// Publishers are artifacts and paths in them for each publisher.
+	Publishers map[string]ArtifactPath `yaml:"publishers"`
+}
+
+type ArtifactPath struct {
+	// Name is the artifact name. If it's empty, Artifacts.Name is used.
+	Name string `yaml:"name"`
+	// Path is the path of files in the artifact.
+	Path string `yaml:"path"`
+}
+
+// Get returns the artifact and the path of files for a publisher, filling in defaults.
+func (a *Artifacts) Get(publisher string) ArtifactPath {
+	p := a.Publishers[publisher]
+	if p.Name == "" {
+		p.Name = a.Name
+	}
+	if p.Name == "" {
+		p.Name = DefaultArtifactName
+	}
+	if p.Path == "" {
+		p.Path = defaultArtifactPaths[publisher]
+	}
+	return p
+}
+
+func (a *Artifacts) Set(publisher string, p ArtifactPath) {
+	if a.Publishers == nil {
+		a.Publishers = map[string]ArtifactPath{}
+	}
+	a.Publishers[publisher] = p
+}
+
+// ReadRgo reads rgo's configuration file.
+// If cfgFilePath is empty, .rgo.yaml or .rgo.yml is read if it exists.
+func ReadRgo(fs afero.Fs, cfgFilePath string) (*Rgo, error) {
+	if cfgFilePath != "" {
+		return readRgoFile(fs, cfgFilePath)
+	}
+	for _, p := range []string{".rgo.yaml", ".rgo.yml"} {
+		cfg, err := readRgoFile(fs, p)
+		if err == nil {
+			return cfg, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return &Rgo{}, nil
+}
+
+func readRgoFile(fs afero.Fs, p string) (*Rgo, error) {
+	f, err := fs.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("open a config file: %w", err)
+	}
+	defer f.Close()
+	cfg := &Rgo{}
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("decode a config file as YAML: %w", err)
+	}
+	return cfg, nil
+}
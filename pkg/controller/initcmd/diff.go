@@ -0,0 +1,78 @@
+package initcmd
+
+import (
+	"strings"
+)
+
+const diffContext = 3
+
+// lineDiff returns a line-based diff of two texts in the unified format without hunk headers.
+// Hunks are separated by "...".
+func lineDiff(before, after string) string {
+	a := strings.SplitAfter(before, "\n")
+	b := strings.SplitAfter(after, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// Show changed lines and diffContext lines around them.
+	show := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for m := max(0, k-diffContext); m <= min(len(lines)-1, k+diffContext); m++ {
+			show[m] = true
+		}
+	}
+	sb := &strings.Builder{}
+	last := -1
+	for k, l := range lines {
+		if !show[k] || l.text == "" {
+			continue
+		}
+		if last != -1 && last != k-1 {
+			sb.WriteString("...\n")
+		}
+		last = k
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		if !strings.HasSuffix(l.text, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
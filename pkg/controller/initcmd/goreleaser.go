@@ -0,0 +1,31 @@
+package initcmd
+
+import (
+	"slices"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+	"github.com/suzuki-shunsuke/rgo/pkg/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+// setSkipUpload sets skip_upload: true to each publisher in .goreleaser.yaml
+// so that GoReleaser doesn't push files, and returns publishers found in the file.
+func setSkipUpload(doc *yaml.Node) []string {
+	var publishers []string
+	root := doc.Content[0]
+	for _, pk := range config.PublisherKeys {
+		seq := yamlnode.Get(root, pk.Key)
+		if seq == nil || seq.Kind != yaml.SequenceNode || len(seq.Content) == 0 {
+			continue
+		}
+		for _, item := range seq.Content {
+			if item.Kind == yaml.MappingNode {
+				yamlnode.Set(item, "skip_upload", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+			}
+		}
+		if !slices.Contains(publishers, pk.Publisher) {
+			publishers = append(publishers, pk.Publisher)
+		}
+	}
+	return publishers
+}
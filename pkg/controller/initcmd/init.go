@@ -0,0 +1,137 @@
+package initcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+	"github.com/suzuki-shunsuke/rgo/pkg/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+type Controller struct {
+	fs    afero.Fs
+	param *Param
+}
+
+type Param struct {
+	ConfigFilePath    string
+	RgoConfigFilePath string
+	// Workflow is the file name or path of the release workflow.
+	// A file name is resolved in .github/workflows.
+	Workflow string
+	// Write applies changes to files. Otherwise changes are only printed.
+	Write  bool
+	Stdout io.Writer
+}
+
+func New(fs afero.Fs, param *Param) *Controller {
+	return &Controller{
+		fs:    fs,
+		param: param,
+	}
+}
+
+const filePermission = 0o644
+
+// Init sets skip_upload: true to publishers in .goreleaser.yaml and adds steps uploading files to GitHub Actions Artifacts to the release workflow.
+// Changes are printed as diffs, and they're written only if Param.Write is true.
+// Comments in YAML files are kept, but the indentation is normalized.
+func (c *Controller) Init(_ context.Context, logger *slog.Logger) error {
+	cfgPath, err := config.FilePath(c.fs, c.param.ConfigFilePath)
+	if err != nil {
+		return fmt.Errorf("find a config file: %w", err)
+	}
+	rgo, err := config.ReadRgo(c.fs, c.param.RgoConfigFilePath)
+	if err != nil {
+		return fmt.Errorf("read a rgo config file: %w", err)
+	}
+
+	cfgNode, err := c.readYAML(cfgPath)
+	if err != nil {
+		return err
+	}
+	publishers := setSkipUpload(cfgNode)
+	if len(publishers) == 0 {
+		return fmt.Errorf("no publisher supported by rgo is found in %s", cfgPath)
+	}
+	logger.Info("found publishers", "publishers", publishers)
+	if err := c.update(logger, cfgPath, cfgNode); err != nil {
+		return err
+	}
+
+	workflowPath := config.WorkflowFilePath(c.param.Workflow)
+	workflowNode, err := c.readYAML(workflowPath)
+	if err != nil {
+		return err
+	}
+	if err := addUploadSteps(workflowNode, uploads(publishers, rgo)); err != nil {
+		return fmt.Errorf("update %s: %w", workflowPath, err)
+	}
+	return c.update(logger, workflowPath, workflowNode)
+}
+
+func (c *Controller) readYAML(p string) (*yaml.Node, error) {
+	node, err := yamlnode.Read(c.fs, p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s isn't found", p)
+		}
+		return nil, err //nolint:wrapcheck
+	}
+	return node, nil
+}
+
+// update prints the diff of a file and writes it if Param.Write is true.
+// A file isn't changed if the change is only formatting.
+func (c *Controller) update(logger *slog.Logger, p string, node *yaml.Node) error {
+	before, err := afero.ReadFile(c.fs, p)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", p, err)
+	}
+	after, err := encodeYAML(node)
+	if err != nil {
+		return fmt.Errorf("encode %s as YAML: %w", p, err)
+	}
+	orig := &yaml.Node{}
+	if err := yaml.Unmarshal(before, orig); err != nil {
+		return fmt.Errorf("parse %s as YAML: %w", p, err)
+	}
+	normalized, err := encodeYAML(orig)
+	if err != nil {
+		return fmt.Errorf("encode %s as YAML: %w", p, err)
+	}
+	if bytes.Equal(normalized, after) {
+		logger.Info("no change is necessary", "file", p)
+		return nil
+	}
+
+	fmt.Fprintf(c.param.Stdout, "--- %s\n+++ %s\n%s", p, p, lineDiff(string(before), string(after)))
+	if !c.param.Write {
+		return nil
+	}
+	if err := afero.WriteFile(c.fs, p, after, filePermission); err != nil {
+		return fmt.Errorf("write %s: %w", p, err)
+	}
+	logger.Info("updated a file", "file", p)
+	return nil
+}
+
+func encodeYAML(node *yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2) //nolint:mnd
+	if err := enc.Encode(node); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return buf.Bytes(), nil
+}
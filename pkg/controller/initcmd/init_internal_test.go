@@ -0,0 +1,174 @@
+package initcmd
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+const testGoReleaser = `project_name: foo
+brews:
+  # Homebrew tap
+  - repository:
+      owner: octocat
+      name: homebrew-foo
+scoops:
+  - skip_upload: false # overridden
+    repository:
+      owner: octocat
+      name: scoop-bucket
+winget:
+  - repository:
+      owner: octocat
+      name: winget-pkgs
+`
+
+const testWorkflow = `name: Release
+jobs:
+  release:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      # Release
+      - run: goreleaser release --clean
+      - run: echo done
+`
+
+func TestController_Init(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name        string
+		write       bool
+		rgo         string
+		expCfg      string
+		expWorkflow string
+	}{
+		{
+			name:        "dry run",
+			expCfg:      testGoReleaser,
+			expWorkflow: testWorkflow,
+		},
+		{
+			name:  "write",
+			write: true,
+			rgo: `artifacts:
+  publishers:
+    winget:
+      name: winget
+`,
+			expCfg: `project_name: foo
+brews:
+  # Homebrew tap
+  - repository:
+      owner: octocat
+      name: homebrew-foo
+    skip_upload: true
+scoops:
+  - skip_upload: true # overridden
+    repository:
+      owner: octocat
+      name: scoop-bucket
+winget:
+  - repository:
+      owner: octocat
+      name: winget-pkgs
+    skip_upload: true
+`,
+			expWorkflow: `name: Release
+jobs:
+  release:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      # Release
+      - run: goreleaser release --clean
+      - uses: actions/upload-artifact@ea165f8d65b6e75b540449e92b4886f43607fa02 # v4.6.2
+        with:
+          name: goreleaser
+          path: |
+            dist/homebrew
+            dist/scoop
+            dist/metadata.json
+      - uses: actions/upload-artifact@ea165f8d65b6e75b540449e92b4886f43607fa02 # v4.6.2
+        with:
+          name: winget
+          path: |
+            dist/winget/manifests
+            dist/metadata.json
+      - run: echo done
+`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			files := map[string]string{
+				".goreleaser.yaml":               testGoReleaser,
+				".github/workflows/release.yaml": testWorkflow,
+			}
+			if d.rgo != "" {
+				files[".rgo.yaml"] = d.rgo
+			}
+			for p, s := range files {
+				if err := afero.WriteFile(fs, p, []byte(s), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			stdout := &bytes.Buffer{}
+			c := New(fs, &Param{Workflow: "release.yaml", Write: d.write, Stdout: stdout})
+			if err := c.Init(t.Context(), slog.Default()); err != nil {
+				t.Fatalf("Init() error = %v, want nil", err)
+			}
+			if stdout.Len() == 0 {
+				t.Error("diff isn't printed")
+			}
+			for p, exp := range map[string]string{
+				".goreleaser.yaml":               d.expCfg,
+				".github/workflows/release.yaml": d.expWorkflow,
+			} {
+				b, err := afero.ReadFile(fs, p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(exp, string(b)); diff != "" {
+					t.Errorf("%s mismatch (-want +got):\n%s", p, diff)
+				}
+			}
+			if !d.write {
+				return
+			}
+			// Running again doesn't change anything.
+			stdout.Reset()
+			if err := c.Init(t.Context(), slog.Default()); err != nil {
+				t.Fatalf("Init() error = %v, want nil", err)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("unexpected diff:\n%s", stdout.String())
+			}
+		})
+	}
+}
+
+func Test_lineDiff(t *testing.T) {
+	t.Parallel()
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	after := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj\n"
+	exp := ` b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+ i
++j
+`
+	if diff := cmp.Diff(exp, lineDiff(before, after)); diff != "" {
+		t.Errorf("lineDiff() mismatch (-want +got):\n%s", diff)
+	}
+}
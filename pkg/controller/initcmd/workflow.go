@@ -0,0 +1,147 @@
+package initcmd
+
+import (
+	"errors"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+	"github.com/suzuki-shunsuke/rgo/pkg/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	uploadArtifactAction  = "actions/upload-artifact"
+	uploadArtifactVersion = "ea165f8d65b6e75b540449e92b4886f43607fa02"
+	uploadArtifactComment = "# v4.6.2"
+	distDir               = "dist"
+	// anchorFile is a file GoReleaser always creates in the dist directory.
+	// actions/upload-artifact strips the least common ancestor of paths,
+	// so the file is uploaded too in order to keep the dist directory as the root of the artifact.
+	anchorFile = "metadata.json"
+)
+
+// upload is an artifact uploaded by actions/upload-artifact.
+type upload struct {
+	name  string
+	paths []string
+}
+
+// uploads returns artifacts and paths in the dist directory which rgo downloads for publishers.
+func uploads(publishers []string, rgo *config.Rgo) []*upload {
+	var ups []*upload
+	get := func(name string) *upload {
+		for _, up := range ups {
+			if up.name == name {
+				return up
+			}
+		}
+		up := &upload{name: name}
+		ups = append(ups, up)
+		return up
+	}
+	add := func(name, p string) {
+		up := get(name)
+		p = path.Join(distDir, p)
+		if !slices.Contains(up.paths, p) {
+			up.paths = append(up.paths, p)
+		}
+	}
+	for _, publisher := range publishers {
+		p := rgo.Artifacts.Get(publisher)
+		add(p.Name, p.Path)
+	}
+	for _, g := range rgo.GitFiles {
+		if g.ArtifactGlob == "" {
+			continue
+		}
+		add(g.ArtifactName(&rgo.Artifacts), g.ArtifactGlob)
+	}
+	for _, up := range ups {
+		add(up.name, anchorFile)
+	}
+	return ups
+}
+
+// addUploadSteps adds steps uploading artifacts after the step running GoReleaser.
+// If a step uploading the same artifact exists, its path is updated instead.
+func addUploadSteps(doc *yaml.Node, ups []*upload) error {
+	jobs := yamlnode.Get(doc.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return errors.New("jobs isn't found")
+	}
+	for i := 1; i < len(jobs.Content); i += 2 {
+		steps := yamlnode.Get(jobs.Content[i], "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		idx := slices.IndexFunc(steps.Content, isGoReleaserStep)
+		if idx == -1 {
+			continue
+		}
+		for _, up := range ups {
+			if step := findUploadStep(steps.Content, up.name); step != nil {
+				yamlnode.Set(yamlnode.Get(step, "with"), "path", pathNode(up.paths))
+				continue
+			}
+			idx++
+			steps.Content = slices.Insert(steps.Content, idx, uploadStep(up))
+		}
+		return nil
+	}
+	return errors.New("the step running GoReleaser isn't found")
+}
+
+func isGoReleaserStep(step *yaml.Node) bool {
+	if uses := yamlnode.Get(step, "uses"); uses != nil && strings.HasPrefix(uses.Value, "goreleaser/goreleaser-action@") {
+		return true
+	}
+	if run := yamlnode.Get(step, "run"); run != nil {
+		for _, line := range strings.Split(run.Value, "\n") {
+			if f := strings.Fields(line); len(f) > 0 && f[0] == "goreleaser" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findUploadStep(steps []*yaml.Node, name string) *yaml.Node {
+	for _, step := range steps {
+		uses := yamlnode.Get(step, "uses")
+		if uses == nil || !strings.HasPrefix(uses.Value, uploadArtifactAction+"@") {
+			continue
+		}
+		with := yamlnode.Get(step, "with")
+		if with == nil {
+			continue
+		}
+		if n := yamlnode.Get(with, "name"); n != nil && n.Value == name {
+			return step
+		}
+	}
+	return nil
+}
+
+func uploadStep(up *upload) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			yamlnode.Str("uses"),
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: uploadArtifactAction + "@" + uploadArtifactVersion, LineComment: uploadArtifactComment},
+			yamlnode.Str("with"),
+			{
+				Kind: yaml.MappingNode,
+				Content: []*yaml.Node{
+					yamlnode.Str("name"), yamlnode.Str(up.name),
+					yamlnode.Str("path"), pathNode(up.paths),
+				},
+			},
+		},
+	}
+}
+
+func pathNode(paths []string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.LiteralStyle, Value: strings.Join(paths, "\n") + "\n"}
+}
@@ -0,0 +1,248 @@
+package run
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v90/github"
+)
+
+const (
+	// maxArtifactSize is the maximum size of a zip archive of an artifact.
+	maxArtifactSize int64 = 1 << 30 // 1 GiB
+	// maxExtractedSize is the maximum total size of files extracted from an artifact.
+	maxExtractedSize int64 = 4 << 30 // 4 GiB
+	maxRedirects           = 10
+	// progressStep is how often the download progress is logged.
+	progressStep = 10 // percent
+)
+
+// downloadArtifacts downloads artifacts of a workflow run via GitHub Actions API.
+// Like `gh run download -n <name> -D <dir>`, each artifact is extracted into <dir>/<artifact name>.
+func (c *Controller) downloadArtifacts(ctx context.Context, logger *slog.Logger, dir, runID string, names []string) error {
+	owner, repo, err := c.getRepository(ctx, logger)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(runID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse the workflow run ID: %w", err)
+	}
+
+	artifacts, err := c.listArtifacts(ctx, owner, repo, id)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		idx := slices.IndexFunc(artifacts, func(artifact *github.Artifact) bool {
+			return artifact.GetName() == name
+		})
+		if idx == -1 {
+			return fmt.Errorf("artifact %s isn't found in the workflow run %s", name, runID)
+		}
+		if err := c.downloadArtifact(ctx, logger, owner, repo, dir, artifacts[idx]); err != nil {
+			return fmt.Errorf("download artifacts: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) listArtifacts(ctx context.Context, owner, repo string, runID int64) ([]*github.Artifact, error) {
+	var artifacts []*github.Artifact
+	opts := &github.ListOptions{PerPage: 100} //nolint:mnd
+	for {
+		list, resp, err := c.ghActions.ListWorkflowRunArtifacts(ctx, owner, repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list artifacts of the workflow run: %w", err)
+		}
+		artifacts = append(artifacts, list.Artifacts...)
+		if resp == nil || resp.NextPage == 0 {
+			return artifacts, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *Controller) downloadArtifact(ctx context.Context, logger *slog.Logger, owner, repo, dir string, artifact *github.Artifact) error {
+	name := artifact.GetName()
+	logger = logger.With("artifact", name, "size", artifact.GetSizeInBytes())
+	if artifact.GetExpired() {
+		return fmt.Errorf("artifact %s has expired", name)
+	}
+	if artifact.GetSizeInBytes() > maxArtifactSize {
+		return fmt.Errorf("artifact %s is too large: %d bytes > %d bytes", name, artifact.GetSizeInBytes(), maxArtifactSize)
+	}
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("artifact name is invalid: %s", name)
+	}
+
+	u, _, err := c.ghActions.DownloadArtifact(ctx, owner, repo, artifact.GetID(), maxRedirects)
+	if err != nil {
+		return fmt.Errorf("get the download URL of the artifact %s: %w", name, err)
+	}
+
+	zipPath := filepath.Join(dir, name+".zip")
+	logger.Info("downloading an artifact")
+	if err := c.downloadFile(ctx, logger, u.String(), zipPath, artifact); err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.fs.Remove(zipPath); err != nil {
+			logger.Warn("remove a downloaded zip file", "error", err)
+		}
+	}()
+
+	logger.Info("extracting an artifact")
+	if err := c.extractZip(zipPath, filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("extract the artifact %s: %w", name, err)
+	}
+	return nil
+}
+
+func (c *Controller) downloadFile(ctx context.Context, logger *slog.Logger, u, dst string, artifact *github.Artifact) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("create a HTTP request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send a HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download the artifact %s: status code %d", artifact.GetName(), resp.StatusCode)
+	}
+
+	f, err := c.fs.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create a file: %w", err)
+	}
+	defer f.Close()
+
+	hs := sha256.New()
+	pr := &progressReader{
+		reader: io.LimitReader(resp.Body, maxArtifactSize+1),
+		logger: logger,
+		total:  artifact.GetSizeInBytes(),
+	}
+	n, err := io.Copy(io.MultiWriter(f, hs), pr)
+	if err != nil {
+		return fmt.Errorf("download the artifact %s: %w", artifact.GetName(), err)
+	}
+	if n > maxArtifactSize {
+		return fmt.Errorf("artifact %s is too large: more than %d bytes", artifact.GetName(), maxArtifactSize)
+	}
+	return verifyDigest(artifact, hs)
+}
+
+// verifyDigest compares the SHA256 digest of a downloaded artifact with the one returned by the API.
+// The digest is returned only for artifacts uploaded with actions/upload-artifact v4 or newer.
+func verifyDigest(artifact *github.Artifact, hs hash.Hash) error {
+	digest, ok := strings.CutPrefix(artifact.GetDigest(), "sha256:")
+	if !ok {
+		return nil
+	}
+	if got := hex.EncodeToString(hs.Sum(nil)); got != digest {
+		return fmt.Errorf("digest of the artifact %s doesn't match: got sha256:%s, want sha256:%s", artifact.GetName(), got, digest)
+	}
+	return nil
+}
+
+func (c *Controller) extractZip(zipPath, dst string) error {
+	f, err := c.fs.Open(zipPath)
+	if err != nil {
+		return fmt.Errorf("open a zip file: %w", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("get the zip file information: %w", err)
+	}
+	zr, err := zip.NewReader(f, fi.Size())
+	if err != nil {
+		return fmt.Errorf("read a zip file: %w", err)
+	}
+
+	var extracted int64
+	for _, zf := range zr.File {
+		// Prevent zip slip.
+		if !filepath.IsLocal(zf.Name) {
+			return fmt.Errorf("zip file includes an invalid path: %s", zf.Name)
+		}
+		p := filepath.Join(dst, zf.Name)
+		if zf.FileInfo().IsDir() {
+			if err := c.fs.MkdirAll(p, dirPermission); err != nil {
+				return fmt.Errorf("create a directory: %w", err)
+			}
+			continue
+		}
+		if !zf.Mode().IsRegular() {
+			return fmt.Errorf("zip file includes a file which isn't regular: %s", zf.Name)
+		}
+		n, err := c.extractFile(zf, p, maxExtractedSize-extracted)
+		if err != nil {
+			return err
+		}
+		extracted += n
+	}
+	return nil
+}
+
+func (c *Controller) extractFile(zf *zip.File, dst string, limit int64) (int64, error) {
+	if err := c.fs.MkdirAll(filepath.Dir(dst), dirPermission); err != nil {
+		return 0, fmt.Errorf("create a directory: %w", err)
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return 0, fmt.Errorf("open a file in a zip file: %w", err)
+	}
+	defer rc.Close()
+	f, err := c.fs.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermission)
+	if err != nil {
+		return 0, fmt.Errorf("create a file: %w", err)
+	}
+	defer f.Close()
+	// The uncompressed size in the zip header isn't trusted.
+	n, err := io.Copy(f, io.LimitReader(rc, limit+1))
+	if err != nil {
+		return 0, fmt.Errorf("extract a file %s: %w", zf.Name, err)
+	}
+	if n > limit {
+		return 0, fmt.Errorf("extracted files are too large: more than %d bytes", maxExtractedSize)
+	}
+	return n, nil
+}
+
+// progressReader logs the download progress every progressStep percent.
+type progressReader struct {
+	reader  io.Reader
+	logger  *slog.Logger
+	total   int64
+	read    int64
+	percent int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.read += int64(n)
+	if p.total > 0 {
+		if percent := p.read * 100 / p.total; percent >= p.percent+progressStep { //nolint:mnd
+			p.percent = percent - percent%progressStep
+			p.logger.Info("downloading an artifact", "progress", fmt.Sprintf("%d%%", p.percent), "downloaded", p.read)
+		}
+	}
+	return n, err //nolint:wrapcheck
+}
@@ -0,0 +1,178 @@
+package run
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+)
+
+func newZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newTestServer starts a test server of GitHub API and returns its mux, server, and a GitHub client for it.
+func newTestServer(t *testing.T) (*http.ServeMux, *httptest.Server, *GitHub) {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	baseURL := srv.URL + "/"
+	client, err := github.NewClient(github.WithHTTPClient(srv.Client()), github.WithURLs(&baseURL, &baseURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mux, srv, &GitHub{
+		Actions:    client.Actions,
+		HTTPClient: srv.Client(),
+	}
+}
+
+// newArtifactServer returns a GitHub client for a test server serving artifacts of the workflow run 12345.
+func newArtifactServer(t *testing.T, artifacts map[string][]byte, digest string) *GitHub {
+	t.Helper()
+	mux, srv, gh := newTestServer(t)
+
+	list := &github.ArtifactList{}
+	var n int64
+	for name, b := range artifacts {
+		n++
+		id := n
+		artifact := &github.Artifact{
+			ID:          github.Ptr(id),
+			Name:        github.Ptr(name),
+			SizeInBytes: github.Ptr(int64(len(b))),
+		}
+		if digest != "" {
+			artifact.Digest = github.Ptr(digest)
+		}
+		list.Artifacts = append(list.Artifacts, artifact)
+		mux.HandleFunc(fmt.Sprintf("GET /repos/owner/repo/actions/artifacts/%d/zip", id), func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, fmt.Sprintf("%s/blobs/%d", srv.URL, id), http.StatusFound)
+		})
+		mux.HandleFunc(fmt.Sprintf("GET /blobs/%d", id), func(w http.ResponseWriter, _ *http.Request) {
+			w.Write(b) //nolint:errcheck
+		})
+	}
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/12345/artifacts", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(list); err != nil {
+			t.Error(err)
+		}
+	})
+	return gh
+}
+
+func TestController_downloadArtifacts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		gh := newArtifactServer(t, map[string][]byte{
+			"goreleaser": newZip(t, map[string]string{
+				"homebrew/foo.rb":           "class Foo",
+				"scoop/foo.json":            "{}",
+				"winget/manifests/foo.yaml": "",
+			}),
+			"sbom": newZip(t, map[string]string{"foo.sbom.json": "{}"}),
+		}, "")
+		fs := afero.NewMemMapFs()
+		c := New(fs, &ParamRun{Repository: "owner/repo"}, nil, gh)
+
+		if err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345", []string{"goreleaser"}); err != nil {
+			t.Fatalf("downloadArtifacts() error = %v, want nil", err)
+		}
+		b, err := afero.ReadFile(fs, "/tmp/test/goreleaser/homebrew/foo.rb")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "class Foo" {
+			t.Errorf("homebrew/foo.rb = %s, want class Foo", string(b))
+		}
+		if exists, err := afero.Exists(fs, "/tmp/test/sbom"); err != nil {
+			t.Fatal(err)
+		} else if exists {
+			t.Error("unmatched artifact was downloaded")
+		}
+		if exists, err := afero.Exists(fs, "/tmp/test/goreleaser.zip"); err != nil {
+			t.Fatal(err)
+		} else if exists {
+			t.Error("zip file wasn't removed")
+		}
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		t.Parallel()
+		b := newZip(t, map[string]string{"scoop/foo.json": "{}"})
+		sum := sha256.Sum256(b)
+		gh := newArtifactServer(t, map[string][]byte{"goreleaser": b}, "sha256:"+hex.EncodeToString(sum[:]))
+		c := New(afero.NewMemMapFs(), &ParamRun{Repository: "owner/repo"}, nil, gh)
+		if err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345", []string{"goreleaser"}); err != nil {
+			t.Errorf("downloadArtifacts() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		t.Parallel()
+		gh := newArtifactServer(t, map[string][]byte{
+			"goreleaser": newZip(t, map[string]string{"scoop/foo.json": "{}"}),
+		}, "sha256:0000")
+		c := New(afero.NewMemMapFs(), &ParamRun{Repository: "owner/repo"}, nil, gh)
+		if err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345", []string{"goreleaser"}); err == nil {
+			t.Error("downloadArtifacts() error = nil, want error")
+		}
+	})
+
+	t.Run("zip slip", func(t *testing.T) {
+		t.Parallel()
+		gh := newArtifactServer(t, map[string][]byte{
+			"goreleaser": newZip(t, map[string]string{"../evil.sh": "echo evil"}),
+		}, "")
+		fs := afero.NewMemMapFs()
+		c := New(fs, &ParamRun{Repository: "owner/repo"}, nil, gh)
+		if err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345", []string{"goreleaser"}); err == nil {
+			t.Error("downloadArtifacts() error = nil, want error")
+		}
+		if exists, err := afero.Exists(fs, "/tmp/evil.sh"); err != nil {
+			t.Fatal(err)
+		} else if exists {
+			t.Error("file was extracted out of the directory")
+		}
+	})
+
+	t.Run("artifact isn't found", func(t *testing.T) {
+		t.Parallel()
+		gh := newArtifactServer(t, map[string][]byte{
+			"sbom": newZip(t, map[string]string{"foo.sbom.json": "{}"}),
+		}, "")
+		c := New(afero.NewMemMapFs(), &ParamRun{Repository: "owner/repo"}, nil, gh)
+		if err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345", []string{"goreleaser"}); err == nil {
+			t.Error("downloadArtifacts() error = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+const (
+	// aurBranch is the only branch AUR accepts.
+	aurBranch                   = "master"
+	defaultAURCommitMsgTemplate = "Update to {{ .Tag }}"
+)
+
+func (c *Controller) processAUR(ctx context.Context, logger *slog.Logger, cfg *config.Config, aurDir, workDir string) error {
+	if aurDir != "" {
+		if _, err := c.fs.Stat(aurDir); os.IsNotExist(err) {
+			logger.Info("AUR PKGBUILD isn't found")
+			return nil
+		}
+	}
+
+	return forEach(c.param.KeepGoing, cfg.AURs, func(aur config.AUR) error {
+		return c.pushAUR(ctx, logger, cfg, aur, aurDir, workDir)
+	})
+}
+
+// pushAUR pushes PKGBUILD and .SRCINFO to the AUR git repository over SSH.
+// AUR isn't hosted on GitHub, so pull requests and GitHub API aren't available.
+func (c *Controller) pushAUR(ctx context.Context, logger *slog.Logger, cfg *config.Config, aur config.AUR, aurDir, workDir string) error {
+	if aur.Name == "" {
+		aur.Name = cfg.ProjectName + "-bin"
+	}
+	if aur.GitURL == "" {
+		return fmt.Errorf("git_url of the AUR package %s is required", aur.Name)
+	}
+	if !filepath.IsLocal(aur.Name) {
+		return fmt.Errorf("AUR package name is invalid: %s", aur.Name)
+	}
+	if aur.Directory != "" && !filepath.IsLocal(aur.Directory) {
+		return fmt.Errorf("directory of the AUR package %s must be a relative path in the repository: %s", aur.Name, aur.Directory)
+	}
+
+	vars := c.newTemplateVars(ctx, logger, cfg)
+	_, commitMsg, err := renderRepo(vars, config.Repository{}, aur.CommitMsgTemplate, defaultAURCommitMsgTemplate)
+	if err != nil {
+		return err
+	}
+	author, err := renderAuthor(vars, aur.CommitAuthor)
+	if err != nil {
+		return err
+	}
+
+	t := &repoTarget{
+		key:           "aurs/" + aur.Name,
+		publisher:     "aur",
+		dirName:       aur.Name,
+		commitMessage: commitMsg,
+		author:        author,
+		external:      true,
+		copyFiles: func(repoDir string) ([]string, error) {
+			return c.copyAURFiles(aurDir, repoDir, aur)
+		},
+	}
+	rc := &repoConfig{
+		headBranch: aurBranch,
+		baseBranch: aurBranch,
+		headURL:    aur.GitURL,
+		baseURL:    aur.GitURL,
+	}
+	logger = logger.With("publisher", t.publisher)
+	if c.isPushed(logger, t) {
+		c.recordResult(t, rc, statusSkipped, nil)
+		return nil
+	}
+	return c.pushRepoConfig(ctx, logger, cfg, t, rc, aurDir, workDir)
+}
+
+// copyAURFiles copies <name>.pkgbuild and <name>.srcinfo generated by GoReleaser as PKGBUILD and .SRCINFO.
+func (c *Controller) copyAURFiles(aurDir, repoDir string, aur config.AUR) ([]string, error) {
+	dstDir := filepath.Join(repoDir, aur.Directory)
+	if err := c.fs.MkdirAll(dstDir, dirPermission); err != nil {
+		return nil, fmt.Errorf("create a directory: %w", err)
+	}
+	files := make([]string, 0, 2) //nolint:mnd
+	for _, f := range [][2]string{
+		{aur.Name + ".pkgbuild", "PKGBUILD"},
+		{aur.Name + ".srcinfo", ".SRCINFO"},
+	} {
+		src, dst := f[0], f[1]
+		if err := c.copyFile(filepath.Join(aurDir, src), filepath.Join(dstDir, dst)); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("%s isn't found in the artifact: %w", src, err)
+			}
+			return nil, fmt.Errorf("copy AUR file: %w", err)
+		}
+		files = append(files, filepath.Join(aur.Directory, dst))
+	}
+	return files, nil
+}
@@ -0,0 +1,95 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_pushAUR(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		// remote is the output of git ls-remote.
+		remote string
+		exp    []string
+	}{
+		{
+			name:   "existing package",
+			remote: "abc\trefs/heads/master\n",
+			exp: []string{
+				"git clone --depth 1 --branch master ssh://aur@aur.archlinux.org/foo-bin.git foo-bin",
+				"git add " + filepath.Join("pkg", "PKGBUILD") + " " + filepath.Join("pkg", ".SRCINFO"),
+				"git commit -m Update to v1.0.0",
+				"git push origin master",
+			},
+		},
+		{
+			// The git repository of a new package is empty until the first push.
+			name: "new package",
+			exp: []string{
+				"git clone --depth 1 ssh://aur@aur.archlinux.org/foo-bin.git foo-bin",
+				"git checkout -b master",
+				"git add " + filepath.Join("pkg", "PKGBUILD") + " " + filepath.Join("pkg", ".SRCINFO"),
+				"git commit -m Update to v1.0.0",
+				"git push origin master",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			for name, content := range map[string]string{
+				"/dist/aur/foo-bin.pkgbuild": "pkgname=foo-bin\n",
+				"/dist/aur/foo-bin.srcinfo":  "pkgbase = foo-bin\n",
+			} {
+				if err := afero.WriteFile(fs, name, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var commands []string
+			exec := &mockExecutor{
+				runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+					commands = append(commands, name+" "+strings.Join(args, " "))
+					return nil
+				},
+				outputFunc: func(ctx context.Context, logger *slog.Logger, dir string, name string, args ...string) (string, error) {
+					if args[0] == "ls-remote" {
+						return tt.remote, nil
+					}
+					return outputChanged(ctx, logger, dir, name, args...)
+				},
+			}
+			c := New(fs, &ParamRun{Version: "v1.0.0"}, exec, nil)
+			cfg := &config.Config{
+				ProjectName: "foo",
+				// AUR always uses git even if the GitHub API backend is enabled.
+				Rgo: config.Rgo{Commit: config.RgoCommit{Backend: config.CommitBackendAPI}},
+			}
+			aur := config.AUR{
+				GitURL:    "ssh://aur@aur.archlinux.org/foo-bin.git",
+				Directory: "pkg",
+			}
+			if err := c.pushAUR(t.Context(), slog.Default(), cfg, aur, "/dist/aur", "/work"); err != nil {
+				t.Fatalf("pushAUR() error = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.exp, commands); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+			b, err := afero.ReadFile(fs, "/work/foo-bin/pkg/.SRCINFO")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != "pkgbase = foo-bin\n" {
+				t.Errorf(".SRCINFO = %q", string(b))
+			}
+		})
+	}
+}
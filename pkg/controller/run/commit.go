@@ -0,0 +1,48 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// gitCommitArgs returns arguments of git to commit staged changes.
+// The author and signing settings are passed by -c, so they're applied to both the author and the committer without changing the local git configuration.
+func gitCommitArgs(author config.CommitAuthor, msg string) ([]string, error) {
+	args, err := gitConfigArgs(author)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, "commit", "-m", msg), nil
+}
+
+// gitConfigArgs returns -c options of git for the author and signing settings.
+// They're also used to rebase commits so that rebased commits are signed in the same way.
+func gitConfigArgs(author config.CommitAuthor) ([]string, error) {
+	var args []string
+	if author.Name != "" {
+		args = append(args, "-c", "user.name="+author.Name)
+	}
+	if author.Email != "" {
+		args = append(args, "-c", "user.email="+author.Email)
+	}
+	if signing := author.Signing; signing.Enabled {
+		args = append(args, "-c", "commit.gpgsign=true")
+		programKey := "gpg.program"
+		switch signing.Format {
+		case "", "openpgp":
+		case "x509", "ssh":
+			args = append(args, "-c", "gpg.format="+signing.Format)
+			programKey = "gpg." + signing.Format + ".program"
+		default:
+			return nil, fmt.Errorf("commit_author.signing.format must be openpgp, x509, or ssh: %s", signing.Format)
+		}
+		if signing.Key != "" {
+			args = append(args, "-c", "user.signingkey="+signing.Key)
+		}
+		if signing.Program != "" {
+			args = append(args, "-c", programKey+"="+signing.Program)
+		}
+	}
+	return args, nil
+}
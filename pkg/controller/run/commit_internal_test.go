@@ -0,0 +1,68 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func Test_gitCommitArgs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		author  config.CommitAuthor
+		exp     []string
+		wantErr bool
+	}{
+		{
+			name: "local git configuration",
+			exp:  []string{"commit", "-m", "msg"},
+		},
+		{
+			name:   "author",
+			author: config.CommitAuthor{Name: "octocat", Email: "octocat@example.com"},
+			exp:    []string{"-c", "user.name=octocat", "-c", "user.email=octocat@example.com", "commit", "-m", "msg"},
+		},
+		{
+			name: "gpg",
+			author: config.CommitAuthor{
+				Name:    "octocat",
+				Signing: config.CommitSigning{Enabled: true, Key: "ABCDEF"},
+			},
+			exp: []string{"-c", "user.name=octocat", "-c", "commit.gpgsign=true", "-c", "user.signingkey=ABCDEF", "commit", "-m", "msg"},
+		},
+		{
+			name: "ssh",
+			author: config.CommitAuthor{
+				Signing: config.CommitSigning{Enabled: true, Key: "~/.ssh/id_ed25519.pub", Program: "ssh-keygen", Format: "ssh"},
+			},
+			exp: []string{"-c", "commit.gpgsign=true", "-c", "gpg.format=ssh", "-c", "user.signingkey=~/.ssh/id_ed25519.pub", "-c", "gpg.ssh.program=ssh-keygen", "commit", "-m", "msg"},
+		},
+		{
+			name: "invalid format",
+			author: config.CommitAuthor{
+				Signing: config.CommitSigning{Enabled: true, Format: "pgp"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gitCommitArgs(tt.author, "msg")
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("gitCommitArgs() error = %v, want nil", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("gitCommitArgs() error = nil, want error")
+			}
+			if diff := cmp.Diff(tt.exp, got); diff != "" {
+				t.Errorf("gitCommitArgs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
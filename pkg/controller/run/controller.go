@@ -3,25 +3,74 @@ package run
 import (
 	"context"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
 
 	"github.com/google/go-github/v90/github"
 	"github.com/spf13/afero"
 )
 
 type Controller struct {
-	fs     afero.Fs
-	param  *ParamRun
-	exec   Executor
-	ghRepo RepositoriesClient
-}
-
-func New(fs afero.Fs, param *ParamRun, exec Executor, ghRepo RepositoriesClient) *Controller {
-	return &Controller{
-		param:  param,
-		fs:     fs,
-		exec:   exec,
-		ghRepo: ghRepo,
+	fs        afero.Fs
+	param     *ParamRun
+	exec      Executor
+	ghRepo    RepositoriesClient
+	ghActions ActionsClient
+	ghPR      PullRequestsClient
+	ghGit     GitClient
+	// httpClient downloads artifacts from URLs returned by GitHub Actions API.
+	httpClient *http.Client
+	// journal records finished phases. Run replaces it with one backed by a state file.
+	journal *journal
+	// results is shared by copies of the controller to print the summary.
+	results *results
+	// report records a release. It's set by Run.
+	report *report
+	// pushRetryInterval is the base interval between attempts to push a branch.
+	pushRetryInterval time.Duration
+	// runPollInterval is the interval between requests to get the status of the workflow run.
+	runPollInterval time.Duration
+	// getenv gets environment variables. It's replaced in tests.
+	getenv func(key string) string
+}
+
+// GitHub is a set of GitHub API clients.
+type GitHub struct {
+	Repositories RepositoriesClient
+	Actions      ActionsClient
+	PullRequests PullRequestsClient
+	Git          GitClient
+	// HTTPClient downloads artifacts. If it's nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+func New(fs afero.Fs, param *ParamRun, exec Executor, gh *GitHub) *Controller {
+	c := &Controller{
+		param:      param,
+		fs:         fs,
+		exec:       exec,
+		httpClient: http.DefaultClient,
+		journal: &journal{
+			fs:    fs,
+			state: &state{Version: param.Version},
+		},
+		results:           &results{},
+		pushRetryInterval: defaultPushRetryInterval,
+		runPollInterval:   defaultRunPollInterval,
+		getenv:            os.Getenv,
+	}
+	if gh != nil {
+		c.ghRepo = gh.Repositories
+		c.ghActions = gh.Actions
+		c.ghPR = gh.PullRequests
+		c.ghGit = gh.Git
+		if gh.HTTPClient != nil {
+			c.httpClient = gh.HTTPClient
+		}
 	}
+	return c
 }
 
 type Executor interface {
@@ -31,4 +80,26 @@ type Executor interface {
 
 type RepositoriesClient interface {
 	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+}
+
+type ActionsClient interface {
+	ListWorkflowRunsByFileName(ctx context.Context, owner, repo, workflowFileName string, opts *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error)
+	GetWorkflowRunByID(ctx context.Context, owner, repo string, runID int64) (*github.WorkflowRun, *github.Response, error)
+	ListWorkflowRunArtifacts(ctx context.Context, owner, repo string, runID int64, opts *github.ListOptions) (*github.ArtifactList, *github.Response, error)
+	DownloadArtifact(ctx context.Context, owner, repo string, artifactID int64, maxRedirects int) (*url.URL, *github.Response, error)
+}
+
+type PullRequestsClient interface {
+	Create(ctx context.Context, owner, repo string, body github.CreatePullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+type GitClient interface {
+	GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, *github.Response, error)
+	CreateRef(ctx context.Context, owner, repo string, body github.CreateRef) (*github.Reference, *github.Response, error)
+	UpdateRef(ctx context.Context, owner, repo, ref string, body github.UpdateRef) (*github.Reference, *github.Response, error)
+	GetCommit(ctx context.Context, owner, repo, sha string) (*github.Commit, *github.Response, error)
+	CreateBlob(ctx context.Context, owner, repo string, body github.Blob) (*github.Blob, *github.Response, error)
+	CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error)
+	CreateCommit(ctx context.Context, owner, repo string, commit github.Commit, opts *github.CreateCommitOptions) (*github.Commit, *github.Response, error)
 }
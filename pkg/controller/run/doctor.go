@@ -0,0 +1,251 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// doctorCheck is a result of a preflight check.
+type doctorCheck struct {
+	name string
+	err  error
+	// hint tells how to fix the problem.
+	hint string
+	// skipped is true if the check isn't necessary.
+	skipped bool
+}
+
+// Doctor checks if the local environment is ready to release and prints the result.
+func (c *Controller) Doctor(ctx context.Context, logger *slog.Logger) error {
+	cfg, err := c.readConfig()
+	if err != nil {
+		return err
+	}
+	return c.doctor(ctx, logger, cfg, true)
+}
+
+// doctor runs preflight checks and prints a table of results.
+// If checkout is false, the local checkout isn't checked.
+// rgo run checks it before creating a tag instead so that checks can be skipped by --force.
+func (c *Controller) doctor(ctx context.Context, logger *slog.Logger, cfg *config.Config, checkout bool) error {
+	checks := []*doctorCheck{
+		c.checkCommand(ctx, logger, "git", "Install git and add it to PATH"),
+	}
+	checks = append(checks, c.checkGH(ctx, logger, cfg)...)
+	checks = append(checks, c.checkTokenScopes(ctx, logger))
+	for _, repo := range c.pushedRepos(cfg) {
+		checks = append(checks, c.checkPushAccess(ctx, repo))
+	}
+	if checkout {
+		checks = append(checks, c.checkCleanCheckout(ctx, logger), c.checkHEADPushed(ctx, logger))
+	}
+
+	failed := 0
+	var out io.Writer = io.Discard
+	if c.param.Stdout != nil {
+		out = c.param.Stdout
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(w, "CHECK\tSTATUS\tHINT")
+	for _, check := range checks {
+		if check.skipped {
+			fmt.Fprintf(w, "%s\tskip\t%s\n", check.name, check.hint)
+			continue
+		}
+		if check.err == nil {
+			fmt.Fprintf(w, "%s\tok\t\n", check.name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "%s\tfail\t%s (%v)\n", check.name, check.hint, check.err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("output results of checks: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	return nil
+}
+
+func (c *Controller) checkCommand(ctx context.Context, logger *slog.Logger, name, hint string) *doctorCheck {
+	check := &doctorCheck{
+		name: name + " is installed",
+		hint: hint,
+	}
+	if _, err := c.exec.Output(ctx, logger, "", name, "--version"); err != nil {
+		check.err = err
+	}
+	return check
+}
+
+// checkGH checks if GitHub CLI is installed and authenticated.
+// GitHub CLI is used only to open pages to create pull requests, to enable auto-merge, and to get a token if GITHUB_TOKEN and GH_TOKEN aren't set.
+// Otherwise the checks are skipped.
+func (c *Controller) checkGH(ctx context.Context, logger *slog.Logger, cfg *config.Config) []*doctorCheck {
+	installed := &doctorCheck{name: "gh is installed"}
+	auth := &doctorCheck{name: "gh is authenticated"}
+	if !c.usesGH(cfg) {
+		for _, check := range []*doctorCheck{installed, auth} {
+			check.skipped = true
+			check.hint = "GitHub CLI isn't used"
+		}
+		return []*doctorCheck{installed, auth}
+	}
+	installed = c.checkCommand(ctx, logger, "gh", "Install GitHub CLI (https://cli.github.com) and add it to PATH")
+	auth.hint = "Run gh auth login or set GH_TOKEN"
+	if _, err := c.exec.Output(ctx, logger, "", "gh", "auth", "status"); err != nil {
+		auth.err = err
+	}
+	return []*doctorCheck{installed, auth}
+}
+
+// usesGH returns true if GitHub CLI is necessary.
+func (c *Controller) usesGH(cfg *config.Config) bool {
+	if c.param.Web || c.param.AutoMerge || cfg.Rgo.PullRequest.AutoMerge {
+		return true
+	}
+	return c.getenv("GITHUB_TOKEN") == "" && c.getenv("GH_TOKEN") == ""
+}
+
+// checkTokenScopes checks if the classic token has the repo scope.
+// Fine-grained tokens and GitHub App tokens don't have scopes, so they're checked by checkPushAccess.
+func (c *Controller) checkTokenScopes(ctx context.Context, logger *slog.Logger) *doctorCheck {
+	check := &doctorCheck{
+		name: "GitHub token has the repo scope",
+		hint: "Run gh auth refresh -s repo",
+	}
+	owner, repo, err := c.getRepository(ctx, logger)
+	if err != nil {
+		check.err = err
+		check.hint = "Set --repo or the remote origin"
+		return check
+	}
+	_, resp, err := c.ghRepo.Get(ctx, owner, repo)
+	if err != nil {
+		check.err = fmt.Errorf("get repository: %w", err)
+		return check
+	}
+	if resp == nil || resp.Response == nil {
+		return check
+	}
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if header == "" {
+		return check
+	}
+	for scope := range strings.SplitSeq(header, ",") {
+		if s := strings.TrimSpace(scope); s == "repo" || s == "public_repo" {
+			return check
+		}
+	}
+	check.err = fmt.Errorf("scopes: %s", header)
+	return check
+}
+
+func (c *Controller) checkPushAccess(ctx context.Context, fullName string) *doctorCheck {
+	check := &doctorCheck{
+		name: "push access to " + fullName,
+		hint: "Grant the token write access to " + fullName,
+	}
+	owner, name, _ := strings.Cut(fullName, "/")
+	r, _, err := c.ghRepo.Get(ctx, owner, name)
+	if err != nil {
+		check.err = fmt.Errorf("get repository: %w", err)
+		return check
+	}
+	if !r.GetPermissions().GetPush() {
+		check.err = errors.New("no push permission")
+	}
+	return check
+}
+
+func (c *Controller) checkCleanCheckout(ctx context.Context, logger *slog.Logger) *doctorCheck {
+	check := &doctorCheck{
+		name: "working tree is clean",
+		hint: "Commit or stash changes",
+	}
+	out, err := c.exec.Output(ctx, logger, "", "git", "status", "--porcelain")
+	if err != nil {
+		check.err = err
+	} else if out != "" {
+		check.err = errors.New("uncommitted changes exist")
+	}
+	return check
+}
+
+// checkHEADPushed checks if the tagged commit exists on the remote.
+// Otherwise the release workflow can't check out the commit.
+func (c *Controller) checkHEADPushed(ctx context.Context, logger *slog.Logger) *doctorCheck {
+	check := &doctorCheck{
+		name: "HEAD exists on the remote",
+		hint: "Push the current commit",
+	}
+	out, err := c.exec.Output(ctx, logger, "", "git", "branch", "-r", "--contains", "HEAD")
+	if err != nil {
+		check.err = err
+	} else if out == "" {
+		check.err = errors.New("no remote branch contains HEAD")
+	}
+	return check
+}
+
+// pushedRepos returns GitHub repositories (<owner>/<name>) which enabled publishers push commits to.
+// Repositories whose names are templates are excluded.
+func (c *Controller) pushedRepos(cfg *config.Config) []string {
+	var repos []config.Repository
+	if c.shouldPublish("homebrew") {
+		for _, brew := range cfg.Brews {
+			repos = append(repos, brew.Repository)
+		}
+		for _, cask := range cfg.HomebrewCasks {
+			repos = append(repos, cask.Repository)
+		}
+	}
+	if c.shouldPublish("scoop") {
+		for _, scoop := range cfg.Scoops {
+			repos = append(repos, scoop.Repository)
+		}
+	}
+	if c.shouldPublish("winget") {
+		for _, winget := range cfg.Winget {
+			repos = append(repos, winget.Repository)
+		}
+	}
+	if c.shouldPublish("nix") {
+		for _, nix := range cfg.Nix {
+			repos = append(repos, nix.Repository)
+		}
+	}
+	if c.shouldPublish("krew") {
+		for _, krew := range cfg.Krews {
+			repos = append(repos, krew.Repository)
+		}
+	}
+	var names []string
+	for _, repo := range repos {
+		if repo.Owner != "" && repo.Name != "" {
+			names = append(names, repo.Owner+"/"+repo.Name)
+		}
+	}
+	if c.shouldPublish("git_files") {
+		for _, g := range cfg.Rgo.GitFiles {
+			names = append(names, g.Repository)
+		}
+	}
+	var ret []string
+	for _, name := range names {
+		if strings.Contains(name, "{{") || slices.Contains(ret, name) {
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret
+}
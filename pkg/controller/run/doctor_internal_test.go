@@ -0,0 +1,146 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_doctor(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name     string
+		checkout bool
+		outputs  map[string]string
+		scopes   string
+		push     bool
+		// token is true if GITHUB_TOKEN is set.
+		token bool
+		// ghMissing is true if GitHub CLI isn't installed.
+		ghMissing bool
+		exp       []string
+		isErr     bool
+	}{
+		{
+			name:     "pass",
+			checkout: true,
+			outputs: map[string]string{
+				"git branch -r --contains HEAD": "origin/main",
+			},
+			scopes: "repo, workflow",
+			push:   true,
+			exp: []string{
+				"git is installed  ok",
+				"push access to octocat/homebrew-foo  ok",
+				"HEAD exists on the remote  ok",
+			},
+		},
+		{
+			name:     "fail",
+			checkout: true,
+			outputs: map[string]string{
+				"git status --porcelain": " M main.go",
+			},
+			scopes: "read:org",
+			exp: []string{
+				"GitHub token has the repo scope  fail    Run gh auth refresh -s repo (scopes: read:org)",
+				"push access to octocat/homebrew-foo  fail    Grant the token write access to octocat/homebrew-foo (no push permission)",
+				"working tree is clean  fail    Commit or stash changes (uncommitted changes exist)",
+				"HEAD exists on the remote  fail    Push the current commit (no remote branch contains HEAD)",
+			},
+			isErr: true,
+		},
+		{
+			name: "skip checkout",
+			push: true,
+			exp:  []string{"gh is authenticated  ok"},
+		},
+		{
+			name:      "gh isn't used",
+			scopes:    "repo",
+			push:      true,
+			token:     true,
+			ghMissing: true,
+			exp: []string{
+				"gh is installed  skip  GitHub CLI isn't used",
+				"gh is authenticated  skip  GitHub CLI isn't used",
+			},
+		},
+		{
+			name:      "gh is missing",
+			scopes:    "repo",
+			push:      true,
+			ghMissing: true,
+			exp: []string{
+				"gh is installed  fail",
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			exec := &mockExecutor{
+				outputFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) (string, error) {
+					cmd := name + " " + strings.Join(args, " ")
+					if d.ghMissing && name == "gh" {
+						return "", errors.New("executable file not found in $PATH")
+					}
+					if !d.checkout && (strings.HasPrefix(cmd, "git status") || strings.HasPrefix(cmd, "git branch")) {
+						return "", errors.New("the checkout must not be checked")
+					}
+					return d.outputs[cmd], nil
+				},
+			}
+			stdout := &bytes.Buffer{}
+			c := New(afero.NewMemMapFs(), &ParamRun{Repository: "octocat/foo", Stdout: stdout}, exec, &GitHub{
+				Repositories: &mockRepositoriesClient{
+					getFunc: func(_ context.Context, _, _ string) (*github.Repository, *github.Response, error) {
+						resp := &github.Response{Response: &http.Response{Header: http.Header{}}}
+						resp.Header.Set("X-OAuth-Scopes", d.scopes)
+						return &github.Repository{Permissions: &github.RepositoryPermissions{Push: github.Ptr(d.push)}}, resp, nil
+					},
+				},
+			})
+			c.getenv = func(key string) string {
+				if d.token && key == "GITHUB_TOKEN" {
+					return "xxx"
+				}
+				return ""
+			}
+			cfg := &config.Config{
+				Brews: []config.Brew{{Repository: config.Repository{Owner: "octocat", Name: "homebrew-foo"}}},
+			}
+			err := c.doctor(t.Context(), slog.Default(), cfg, d.checkout)
+			if err != nil {
+				if !d.isErr {
+					t.Fatalf("doctor() error = %v, want nil", err)
+				}
+			} else if d.isErr {
+				t.Fatal("doctor() error = nil, want error")
+			}
+			// Normalize padding of the table.
+			var lines []string
+			for line := range strings.SplitSeq(stdout.String(), "\n") {
+				lines = append(lines, strings.Join(strings.Fields(line), " "))
+			}
+			out := strings.Join(lines, "\n")
+			for _, exp := range d.exp {
+				if !strings.Contains(out, strings.Join(strings.Fields(exp), " ")) {
+					t.Errorf("output doesn't include %q:\n%s", exp, stdout.String())
+				}
+			}
+			if !d.checkout && strings.Contains(out, "working tree") {
+				t.Errorf("the checkout is checked:\n%s", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// runOrPrint executes a command which changes the state of a repository.
+// In dry-run mode the command is printed instead of being executed.
+func (c *Controller) runOrPrint(ctx context.Context, logger *slog.Logger, dir string, name string, args ...string) error {
+	if !c.param.DryRun {
+		return c.exec.Run(ctx, logger, dir, name, args...) //nolint:wrapcheck
+	}
+	if dir == "" {
+		c.printf("[dry-run] %s\n", formatCommand(name, args...))
+		return nil
+	}
+	c.printf("[dry-run] (in %s) %s\n", dir, formatCommand(name, args...))
+	return nil
+}
+
+// printDiff prints staged changes of a repository in dry-run mode.
+func (c *Controller) printDiff(ctx context.Context, logger *slog.Logger, repoDir string) error {
+	if !c.param.DryRun {
+		return nil
+	}
+	diff, err := c.exec.Output(ctx, logger, repoDir, "git", "diff", "--cached")
+	if err != nil {
+		return fmt.Errorf("get staged changes: %w", err)
+	}
+	c.printf("[dry-run] (in %s) changes:\n%s\n", repoDir, diff)
+	return nil
+}
+
+// printRepoPlan prints how a repository would be updated when artifacts aren't downloaded.
+func (c *Controller) printRepoPlan(repoURL, branch, commitMsg string) {
+	c.printf("[dry-run] push a commit %q to %s (branch: %s)\n", commitMsg, repoURL, branch)
+}
+
+func (c *Controller) printf(format string, a ...any) {
+	if c.param.Stdout == nil {
+		return
+	}
+	fmt.Fprintf(c.param.Stdout, format, a...)
+}
+
+func formatCommand(name string, args ...string) string {
+	elems := make([]string, 0, len(args)+1)
+	elems = append(elems, name)
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		elems = append(elems, arg)
+	}
+	return strings.Join(elems, " ")
+}
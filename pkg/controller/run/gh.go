@@ -4,6 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v90/github"
 )
 
 func (c *Controller) getDefaultBranch(ctx context.Context, logger *slog.Logger, owner, repo string) (string, error) {
@@ -15,24 +20,122 @@ func (c *Controller) getDefaultBranch(ctx context.Context, logger *slog.Logger,
 	return r.GetDefaultBranch(), nil
 }
 
+const (
+	defaultRunDiscoveryTimeout = 5 * time.Minute
+	minRunDiscoveryInterval    = 2 * time.Second
+	maxRunDiscoveryInterval    = 30 * time.Second
+)
+
+// getRunID finds the workflow run triggered by the pushed tag.
+// It polls the GitHub Actions API with exponential backoff until a run whose head branch and head SHA match the tag is found.
 func (c *Controller) getRunID(ctx context.Context, logger *slog.Logger, workflow string) (string, error) {
-	runID, err := c.exec.Output(ctx, logger, "", "gh", "run", "list", "-w", workflow, "-L", "1", "--json", "databaseId", "--jq", ".[].databaseId")
+	owner, repo, err := c.getRepository(ctx, logger)
 	if err != nil {
-		return "", fmt.Errorf("get workflow run ID: %w", err)
+		return "", err
+	}
+	sha, err := c.exec.Output(ctx, logger, "", "git", "rev-parse", c.param.Version+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("get the commit of the tag: %w", err)
+	}
+
+	timeout := c.param.RunDiscoveryTimeout
+	if timeout == 0 {
+		timeout = defaultRunDiscoveryTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	interval := minRunDiscoveryInterval
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:  c.param.Version,
+		HeadSHA: sha,
+	}
+	for {
+		logger.Info("looking for the workflow run", "workflow", workflow, "tag", c.param.Version, "sha", sha)
+		runs, _, err := c.ghActions.ListWorkflowRunsByFileName(ctx, owner, repo, workflow, opts)
+		if err != nil {
+			return "", fmt.Errorf("list workflow runs: %w", err)
+		}
+		for _, run := range runs.WorkflowRuns {
+			// The API returns runs in descending order of creation, so the first matched run is the latest.
+			if run.GetHeadBranch() == c.param.Version && run.GetHeadSHA() == sha {
+				return strconv.FormatInt(run.GetID(), 10), nil
+			}
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return "", fmt.Errorf("workflow run of %s for the tag %s (%s) isn't found in %s", workflow, c.param.Version, sha, timeout)
+		}
+		if err := wait(ctx, interval); err != nil {
+			return "", err
+		}
+		interval = min(interval*2, maxRunDiscoveryInterval) //nolint:mnd
 	}
-	return runID, nil
 }
 
-func (c *Controller) watchRun(ctx context.Context, logger *slog.Logger, runID string) error {
-	if err := c.exec.Run(ctx, logger, "", "gh", "run", "watch", "--exit-status", runID); err != nil {
-		return fmt.Errorf("wait for workflow run: %w", err)
+// getRepository returns the owner and name of the released repository.
+// If the repository isn't specified, it's parsed from the URL of the remote origin.
+func (c *Controller) getRepository(ctx context.Context, logger *slog.Logger) (string, string, error) {
+	if c.param.Repository != "" {
+		owner, repo, ok := strings.Cut(c.param.Repository, "/")
+		if !ok || owner == "" || repo == "" {
+			return "", "", fmt.Errorf("repository must be <owner>/<name>: %s", c.param.Repository)
+		}
+		return owner, repo, nil
+	}
+	remoteURL, err := c.exec.Output(ctx, logger, "", "git", "remote", "get-url", "origin")
+	if err != nil {
+		return "", "", fmt.Errorf("get the URL of the remote origin: %w", err)
 	}
-	return nil
+	owner, repo, err := parseRepoURL(remoteURL)
+	if err != nil {
+		return "", "", err
+	}
+	return owner, repo, nil
+}
+
+// parseRepoURL parses a git remote URL such as https://github.com/owner/repo.git and git@github.com:owner/repo.git.
+func parseRepoURL(remoteURL string) (string, string, error) {
+	p := remoteURL
+	if _, after, ok := strings.Cut(p, "://"); ok {
+		// https://github.com/owner/repo, ssh://git@github.com/owner/repo
+		_, p, _ = strings.Cut(after, "/")
+	} else if _, after, ok := strings.Cut(p, ":"); ok {
+		// git@github.com:owner/repo
+		p = after
+	}
+	p = strings.TrimSuffix(strings.TrimSuffix(p, "/"), ".git")
+	owner, repo, ok := strings.Cut(p, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("parse the repository URL: %s", remoteURL)
+	}
+	return owner, repo, nil
 }
 
-func (c *Controller) downloadArtifacts(ctx context.Context, logger *slog.Logger, dir, runID string) error {
-	if err := c.exec.Run(ctx, logger, "", "gh", "run", "download", runID, "--pattern", "goreleaser", "-D", dir); err != nil {
-		return fmt.Errorf("download artifacts: %w", err)
+const defaultRunPollInterval = 10 * time.Second
+
+// watchRun waits for the workflow run to complete.
+// It polls the GitHub Actions API until the status of the run is completed, and returns an error unless the run succeeded.
+func (c *Controller) watchRun(ctx context.Context, logger *slog.Logger, runID string) error {
+	id, err := strconv.ParseInt(runID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse the workflow run ID: %w", err)
+	}
+	owner, repo, err := c.getRepository(ctx, logger)
+	if err != nil {
+		return err
+	}
+	for {
+		run, _, err := c.ghActions.GetWorkflowRunByID(ctx, owner, repo, id)
+		if err != nil {
+			return fmt.Errorf("get the workflow run: %w", err)
+		}
+		logger.Info("workflow run", "status", run.GetStatus(), "url", run.GetHTMLURL())
+		if run.GetStatus() == "completed" {
+			if run.GetConclusion() != "success" {
+				return fmt.Errorf("workflow run %d concluded with %s: %s", id, run.GetConclusion(), run.GetHTMLURL())
+			}
+			return nil
+		}
+		if err := wait(ctx, c.runPollInterval); err != nil {
+			return err
+		}
 	}
-	return nil
 }
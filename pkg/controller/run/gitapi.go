@@ -0,0 +1,173 @@
+package run
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// useGitDataAPI returns true if commits are created via GitHub Git Data API instead of git.
+func useGitDataAPI(cfg *config.Config) bool {
+	return cfg.Rgo.Commit.Backend == config.CommitBackendAPI
+}
+
+// commitViaAPI creates a commit via GitHub Git Data API and updates the head branch without cloning the repository.
+// Files are copied into workDir/dirName and uploaded as blobs.
+// GitHub signs commits created via the API, so they're marked as verified.
+// commit_author isn't applied because the commit wouldn't be signed by GitHub if the committer were changed.
+// It returns the SHA of the created commit.
+func (c *Controller) commitViaAPI(ctx context.Context, logger *slog.Logger, t *repoTarget, rc *repoConfig, workDir string) (string, error) {
+	if t.author.Name != "" || t.author.Email != "" || t.author.Signing.Enabled {
+		logger.Warn("commit_author is ignored as commits are created via GitHub API")
+	}
+
+	dir := filepath.Join(workDir, t.dirName)
+	if err := c.fs.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("remove a directory: %w", err)
+	}
+	if err := c.fs.MkdirAll(dir, dirPermission); err != nil {
+		return "", fmt.Errorf("create a directory: %w", err)
+	}
+	files, err := t.copyFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", errors.New("no file is found in the artifact")
+	}
+
+	// The head branch is created from the base branch in pull request mode.
+	parentSHA, err := c.getBranchSHA(ctx, rc.baseOwner, rc.baseName, rc.baseBranch)
+	if err != nil {
+		return "", err
+	}
+
+	if c.param.DryRun {
+		c.printf("[dry-run] create a commit %q on %s (branch: %s, parent: %s) via GitHub API with files:\n", t.commitMessage, rc.headURL, rc.headBranch, parentSHA)
+		for _, file := range files {
+			c.printf("  %s\n", filepath.ToSlash(file))
+		}
+		return "", nil
+	}
+
+	logger.Info("creating a commit via GitHub API", "repo", rc.headURL, "branch", rc.headBranch, "parent", parentSHA)
+	parent, _, err := c.ghGit.GetCommit(ctx, rc.baseOwner, rc.baseName, parentSHA)
+	if err != nil {
+		return "", fmt.Errorf("get the parent commit: %w", err)
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(files))
+	for _, file := range files {
+		entry, err := c.createBlob(ctx, rc, dir, file)
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, entry)
+	}
+
+	tree, _, err := c.ghGit.CreateTree(ctx, rc.headOwner, rc.headName, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return "", fmt.Errorf("create a tree: %w", err)
+	}
+	if tree.GetSHA() == parent.GetTree().GetSHA() {
+		return "", errUpToDate
+	}
+
+	commit, _, err := c.ghGit.CreateCommit(ctx, rc.headOwner, rc.headName, github.Commit{
+		Message: github.Ptr(t.commitMessage),
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: []*github.Commit{{SHA: github.Ptr(parentSHA)}},
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("create a commit: %w", err)
+	}
+	logger.Info("created a commit via GitHub API", "sha", commit.GetSHA())
+
+	if err := c.updateBranch(ctx, rc, commit.GetSHA()); err != nil {
+		return "", err
+	}
+	return commit.GetSHA(), nil
+}
+
+func (c *Controller) createBlob(ctx context.Context, rc *repoConfig, dir, file string) (*github.TreeEntry, error) {
+	b, err := afero.ReadFile(c.fs, filepath.Join(dir, file))
+	if err != nil {
+		return nil, fmt.Errorf("read a file: %w", err)
+	}
+	blob, _, err := c.ghGit.CreateBlob(ctx, rc.headOwner, rc.headName, github.Blob{
+		Content:  github.Ptr(base64.StdEncoding.EncodeToString(b)),
+		Encoding: github.Ptr("base64"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create a blob of %s: %w", file, err)
+	}
+	return &github.TreeEntry{
+		Path: github.Ptr(filepath.ToSlash(file)),
+		Mode: github.Ptr("100644"),
+		Type: github.Ptr("blob"),
+		SHA:  blob.SHA,
+	}, nil
+}
+
+func (c *Controller) getBranchSHA(ctx context.Context, owner, repo, branch string) (string, error) {
+	ref, _, err := c.ghGit.GetRef(ctx, owner, repo, "heads/"+branch)
+	if err != nil {
+		return "", fmt.Errorf("get the branch %s of %s/%s: %w", branch, owner, repo, err)
+	}
+	return ref.GetObject().GetSHA(), nil
+}
+
+// updateBranch points the head branch to a commit.
+// Without pull requests the branch is fast-forwarded, so the update fails if the branch has been changed.
+// In pull request mode the head branch is created, or overwritten if it remains from a previous attempt.
+func (c *Controller) updateBranch(ctx context.Context, rc *repoConfig, sha string) error {
+	ref := "heads/" + rc.headBranch
+	if !rc.pullRequest {
+		if _, _, err := c.ghGit.UpdateRef(ctx, rc.headOwner, rc.headName, ref, github.UpdateRef{SHA: sha}); err != nil {
+			return fmt.Errorf("update the branch %s: %w", rc.headBranch, err)
+		}
+		return nil
+	}
+	_, resp, err := c.ghGit.GetRef(ctx, rc.headOwner, rc.headName, ref)
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("get the branch %s: %w", rc.headBranch, err)
+		}
+		if _, _, err := c.ghGit.CreateRef(ctx, rc.headOwner, rc.headName, github.CreateRef{Ref: "refs/" + ref, SHA: sha}); err != nil {
+			return fmt.Errorf("create the branch %s: %w", rc.headBranch, err)
+		}
+		return nil
+	}
+	if _, _, err := c.ghGit.UpdateRef(ctx, rc.headOwner, rc.headName, ref, github.UpdateRef{SHA: sha, Force: github.Ptr(true)}); err != nil {
+		return fmt.Errorf("update the branch %s: %w", rc.headBranch, err)
+	}
+	return nil
+}
+
+// readFileViaAPI reads a file in a repository via GitHub API.
+// If the file doesn't exist, it returns an empty string.
+func (c *Controller) readFileViaAPI(ctx context.Context, owner, repo, branch, path string) (string, error) {
+	file, _, resp, err := c.ghRepo.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("get %s of %s/%s: %w", path, owner, repo, err)
+	}
+	if file == nil {
+		return "", fmt.Errorf("%s of %s/%s isn't a file", path, owner, repo)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return "", fmt.Errorf("decode %s of %s/%s: %w", path, owner, repo, err)
+	}
+	return content, nil
+}
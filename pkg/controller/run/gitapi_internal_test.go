@@ -0,0 +1,173 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// mockGitClient records requests to GitHub Git Data API.
+type mockGitClient struct {
+	refs map[string]string
+	// treeSHA is the SHA of created trees. If it's empty, "tree" is used.
+	treeSHA string
+	calls   []string
+}
+
+func (m *mockGitClient) GetRef(_ context.Context, owner, repo, ref string) (*github.Reference, *github.Response, error) {
+	m.calls = append(m.calls, "GetRef "+owner+"/"+repo+" "+ref)
+	sha, ok := m.refs[owner+"/"+repo+" "+ref]
+	if !ok {
+		return nil, &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, errors.New("not found")
+	}
+	return &github.Reference{Object: &github.GitObject{SHA: github.Ptr(sha)}}, nil, nil
+}
+
+func (m *mockGitClient) CreateRef(_ context.Context, owner, repo string, body github.CreateRef) (*github.Reference, *github.Response, error) {
+	m.calls = append(m.calls, "CreateRef "+owner+"/"+repo+" "+body.Ref+" "+body.SHA)
+	return &github.Reference{}, nil, nil
+}
+
+func (m *mockGitClient) UpdateRef(_ context.Context, owner, repo, ref string, body github.UpdateRef) (*github.Reference, *github.Response, error) {
+	m.calls = append(m.calls, "UpdateRef "+owner+"/"+repo+" "+ref+" "+body.SHA)
+	return &github.Reference{}, nil, nil
+}
+
+func (m *mockGitClient) GetCommit(_ context.Context, owner, repo, sha string) (*github.Commit, *github.Response, error) {
+	m.calls = append(m.calls, "GetCommit "+owner+"/"+repo+" "+sha)
+	return &github.Commit{Tree: &github.Tree{SHA: github.Ptr("base-tree")}}, nil, nil
+}
+
+func (m *mockGitClient) CreateBlob(_ context.Context, owner, repo string, body github.Blob) (*github.Blob, *github.Response, error) {
+	m.calls = append(m.calls, "CreateBlob "+owner+"/"+repo+" "+body.GetContent())
+	return &github.Blob{SHA: github.Ptr("blob")}, nil, nil
+}
+
+func (m *mockGitClient) CreateTree(_ context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	call := "CreateTree " + owner + "/" + repo + " " + baseTree
+	for _, entry := range entries {
+		call += " " + entry.GetPath() + ":" + entry.GetSHA()
+	}
+	m.calls = append(m.calls, call)
+	if m.treeSHA != "" {
+		return &github.Tree{SHA: github.Ptr(m.treeSHA)}, nil, nil
+	}
+	return &github.Tree{SHA: github.Ptr("tree")}, nil, nil
+}
+
+func (m *mockGitClient) CreateCommit(_ context.Context, owner, repo string, commit github.Commit, _ *github.CreateCommitOptions) (*github.Commit, *github.Response, error) {
+	m.calls = append(m.calls, "CreateCommit "+owner+"/"+repo+" "+commit.GetMessage()+" "+commit.GetTree().GetSHA()+" "+commit.Parents[0].GetSHA())
+	return &github.Commit{SHA: github.Ptr("commit")}, nil, nil
+}
+
+func TestController_commitViaAPI(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		repo config.Repository
+		exp  []string
+	}{
+		{
+			name: "push to the branch",
+			repo: config.Repository{Owner: "octocat", Name: "scoop-bucket", Branch: "main"},
+			exp: []string{
+				"GetRef octocat/scoop-bucket heads/main",
+				"GetCommit octocat/scoop-bucket parent",
+				"CreateBlob octocat/scoop-bucket e30=",
+				"CreateTree octocat/scoop-bucket base-tree foo.json:blob",
+				"CreateCommit octocat/scoop-bucket Scoop update for foo version v1.0.0 tree parent",
+				"UpdateRef octocat/scoop-bucket heads/main commit",
+			},
+		},
+		{
+			name: "create the head branch",
+			repo: config.Repository{
+				Owner: "octocat",
+				Name:  "scoop-bucket",
+				PullRequest: config.PullRequest{
+					Enabled: true,
+					Base:    config.PullRequestBase{Branch: "main"},
+				},
+			},
+			exp: []string{
+				"GetRef octocat/scoop-bucket heads/main",
+				"GetCommit octocat/scoop-bucket parent",
+				"CreateBlob octocat/scoop-bucket e30=",
+				"CreateTree octocat/scoop-bucket base-tree foo.json:blob",
+				"CreateCommit octocat/scoop-bucket Scoop update for foo version v1.0.0 tree parent",
+				"GetRef octocat/scoop-bucket heads/foo-v1.0.0",
+				"CreateRef octocat/scoop-bucket refs/heads/foo-v1.0.0 commit",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "/dist/scoop/foo.json", []byte("{}"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			git := &mockGitClient{refs: map[string]string{"octocat/scoop-bucket heads/main": "parent"}}
+			c := New(fs, &ParamRun{Version: "v1.0.0"}, &mockExecutor{}, &GitHub{Git: git})
+			rc, err := c.buildRepoConfig(t.Context(), slog.Default(), tt.repo, "https://github.com", "foo-v1.0.0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := &repoTarget{
+				dirName:       "scoop-bucket",
+				commitMessage: "Scoop update for foo version v1.0.0",
+				copyFiles: func(repoDir string) ([]string, error) {
+					return c.copyScoopFiles("/dist/scoop", repoDir)
+				},
+			}
+			sha, err := c.commitViaAPI(t.Context(), slog.Default(), target, rc, "/work")
+			if err != nil {
+				t.Fatalf("commitViaAPI() error = %v, want nil", err)
+			}
+			if sha != "commit" {
+				t.Errorf("commitViaAPI() = %q, want %q", sha, "commit")
+			}
+			if diff := cmp.Diff(tt.exp, git.calls); diff != "" {
+				t.Errorf("API calls mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestController_commitViaAPI_upToDate(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/dist/scoop/foo.json", []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The created tree is same as the tree of the parent commit.
+	git := &mockGitClient{refs: map[string]string{"octocat/scoop-bucket heads/main": "parent"}, treeSHA: "base-tree"}
+	c := New(fs, &ParamRun{Version: "v1.0.0"}, &mockExecutor{}, &GitHub{Git: git})
+	rc, err := c.buildRepoConfig(t.Context(), slog.Default(), config.Repository{Owner: "octocat", Name: "scoop-bucket", Branch: "main"}, "https://github.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := &repoTarget{
+		dirName:       "scoop-bucket",
+		commitMessage: "Scoop update for foo version v1.0.0",
+		copyFiles: func(repoDir string) ([]string, error) {
+			return c.copyScoopFiles("/dist/scoop", repoDir)
+		},
+	}
+	if _, err := c.commitViaAPI(t.Context(), slog.Default(), target, rc, "/work"); !errors.Is(err, errUpToDate) {
+		t.Fatalf("commitViaAPI() error = %v, want %v", err, errUpToDate)
+	}
+	for _, call := range git.calls {
+		if strings.HasPrefix(call, "CreateCommit") || strings.HasPrefix(call, "UpdateRef") {
+			t.Errorf("unexpected API call: %s", call)
+		}
+	}
+}
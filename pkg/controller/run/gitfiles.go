@@ -0,0 +1,99 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+const defaultGitFilesCommitMsgTemplate = "Update files of {{ .ProjectName }} to {{ .Tag }}"
+
+func (c *Controller) processGitFiles(ctx context.Context, logger *slog.Logger, cfg *config.Config, layout *artifactLayout, workDir, serverURL string) error {
+	return forEach(c.param.KeepGoing, cfg.Rgo.GitFiles, func(g config.GitFiles) error {
+		return c.pushGitFiles(ctx, logger, cfg, g, layout.artifactDir(g.ArtifactName(&cfg.Rgo.Artifacts)), workDir, serverURL)
+	})
+}
+
+// pushGitFiles pushes files matching artifact_glob in an artifact to <path> in a repository.
+// Each matched file or directory is copied to <path>/<base name>.
+func (c *Controller) pushGitFiles(ctx context.Context, logger *slog.Logger, cfg *config.Config, g config.GitFiles, artifactDir, workDir, serverURL string) error {
+	owner, name, ok := strings.Cut(g.Repository, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("git_files.repository must be <owner>/<name>: %s", g.Repository)
+	}
+	if g.ArtifactGlob == "" {
+		return fmt.Errorf("git_files.artifact_glob is required: %s", g.Repository)
+	}
+	dst := filepath.FromSlash(g.Path)
+	if dst == "" {
+		dst = "."
+	}
+	if !filepath.IsLocal(dst) && dst != "." {
+		return fmt.Errorf("git_files.path must be a relative path in the repository: %s", g.Path)
+	}
+
+	vars := c.newTemplateVars(ctx, logger, cfg)
+	repo, commitMsg, err := renderRepo(vars, config.Repository{
+		Owner:       owner,
+		Name:        name,
+		Branch:      g.Branch,
+		PullRequest: g.PullRequest,
+	}, g.CommitMessage, defaultGitFilesCommitMsgTemplate)
+	if err != nil {
+		return err
+	}
+	return c.pushRepo(ctx, logger, cfg, &repoTarget{
+		key:               repoKey("git_files", owner, name) + "/" + filepath.ToSlash(dst),
+		publisher:         "git_files",
+		repo:              repo,
+		dirName:           name,
+		defaultHeadBranch: c.defaultHeadBranch(cfg.ProjectName),
+		commitMessage:     commitMsg,
+		copyFiles: func(repoDir string) ([]string, error) {
+			return c.copyGitFiles(artifactDir, g.ArtifactGlob, repoDir, dst)
+		},
+	}, artifactDir, workDir, serverURL)
+}
+
+// copyGitFiles copies files matching a glob in artifactDir to repoDir/dst and returns their paths relative to repoDir.
+func (c *Controller) copyGitFiles(artifactDir, glob, repoDir, dst string) ([]string, error) {
+	matches, err := afero.Glob(c.fs, filepath.Join(artifactDir, filepath.FromSlash(glob)))
+	if err != nil {
+		return nil, fmt.Errorf("find files by the glob %s: %w", glob, err)
+	}
+	if len(matches) == 0 {
+		return nil, errors.New("no file matches the glob " + glob)
+	}
+	var files []string
+	for _, match := range matches {
+		base := filepath.Base(match)
+		fi, err := c.fs.Stat(match)
+		if err != nil {
+			return nil, fmt.Errorf("get file information: %w", err)
+		}
+		if !fi.IsDir() {
+			if err := c.fs.MkdirAll(filepath.Join(repoDir, dst), dirPermission); err != nil {
+				return nil, fmt.Errorf("create a directory: %w", err)
+			}
+			if err := c.copyFile(match, filepath.Join(repoDir, dst, base)); err != nil {
+				return nil, err
+			}
+			files = append(files, filepath.Join(dst, base))
+			continue
+		}
+		copied, err := c.copyDir(match, filepath.Join(repoDir, dst, base))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range copied {
+			files = append(files, filepath.Join(dst, base, f))
+		}
+	}
+	return files, nil
+}
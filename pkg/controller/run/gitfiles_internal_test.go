@@ -0,0 +1,69 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_pushGitFiles(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	for _, name := range []string{
+		"/tmp/goreleaser/completions/foo.bash",
+		"/tmp/goreleaser/completions/foo.zsh",
+		"/tmp/goreleaser/version.json",
+	} {
+		if err := afero.WriteFile(fs, name, []byte("foo"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var commands []string
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return nil
+		},
+		outputFunc: outputChanged,
+	}
+	c := New(fs, &ParamRun{Version: "v1.0.0"}, exec, nil)
+	cfg := &config.Config{ProjectName: "foo"}
+	g := config.GitFiles{
+		ArtifactGlob:  "*",
+		Repository:    "octocat/website",
+		Branch:        "main",
+		Path:          "static/foo",
+		CommitMessage: "chore: update foo {{ .Version }}",
+	}
+	if err := c.pushGitFiles(t.Context(), slog.Default(), cfg, g, "/tmp/goreleaser", "/work", "https://github.com"); err != nil {
+		t.Fatalf("pushGitFiles() error = %v, want nil", err)
+	}
+	exp := []string{
+		"git clone --depth 1 --branch main https://github.com/octocat/website website",
+		"git add " + strings.Join([]string{
+			filepath.Join("static", "foo", "completions", "foo.bash"),
+			filepath.Join("static", "foo", "completions", "foo.zsh"),
+			filepath.Join("static", "foo", "version.json"),
+		}, " "),
+		"git commit -m chore: update foo 1.0.0",
+		"git push origin main",
+	}
+	if diff := cmp.Diff(exp, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestController_pushGitFiles_invalidRepository(t *testing.T) {
+	t.Parallel()
+	c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0"}, &mockExecutor{}, nil)
+	g := config.GitFiles{ArtifactGlob: "*", Repository: "website"}
+	if err := c.pushGitFiles(t.Context(), slog.Default(), &config.Config{}, g, "/tmp/goreleaser", "/work", "https://github.com"); err == nil {
+		t.Error("pushGitFiles() error = nil, want error")
+	}
+}
@@ -2,80 +2,76 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"path/filepath"
 
 	"github.com/spf13/afero"
 	"github.com/suzuki-shunsuke/rgo/pkg/config"
 )
 
-func (c *Controller) processHomebrew(ctx context.Context, logger *slog.Logger, cfg *config.Config, tempDir, artifactName, serverURL string) error {
-	homebrewDir := filepath.Join(tempDir, artifactName, "homebrew")
-	if exists, err := afero.Exists(c.fs, homebrewDir); err != nil {
-		return fmt.Errorf("check homebrew directory existence: %w", err)
-	} else if !exists {
-		logger.Info("Homebrew-tap recipe isn't found")
-		return nil
+func (c *Controller) processHomebrew(ctx context.Context, logger *slog.Logger, cfg *config.Config, homebrewDir, workDir, serverURL string) error {
+	if homebrewDir != "" {
+		if exists, err := afero.Exists(c.fs, homebrewDir); err != nil {
+			return fmt.Errorf("check homebrew directory existence: %w", err)
+		} else if !exists {
+			logger.Info("Homebrew-tap recipe isn't found")
+			return nil
+		}
 	}
 
 	// Process homebrew_casks
-	for _, cask := range cfg.HomebrewCasks {
-		if err := c.pushHomebrew(ctx, logger, cask.Repository, cfg.ProjectName, tempDir, artifactName, serverURL); err != nil {
-			return err
-		}
+	err := forEach(c.param.KeepGoing, cfg.HomebrewCasks, func(cask config.HomebrewCask) error {
+		return c.pushHomebrew(ctx, logger, cfg, "homebrew_casks", cask.Repository, cask.CommitMsgTemplate, cask.CommitAuthor, homebrewDir, workDir, serverURL)
+	})
+	if err != nil && !c.param.KeepGoing {
+		return err
 	}
 
 	// Process brews (traditional formula)
-	for _, brew := range cfg.Brews {
-		if err := c.pushHomebrew(ctx, logger, brew.Repository, cfg.ProjectName, tempDir, artifactName, serverURL); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return errors.Join(err, forEach(c.param.KeepGoing, cfg.Brews, func(brew config.Brew) error {
+		return c.pushHomebrew(ctx, logger, cfg, "brews", brew.Repository, brew.CommitMsgTemplate, brew.CommitAuthor, homebrewDir, workDir, serverURL)
+	}))
 }
 
-func (c *Controller) pushHomebrew(ctx context.Context, logger *slog.Logger, repo config.Repository, projectName, tempDir, artifactName, serverURL string) error {
-	repoURL := fmt.Sprintf("%s/%s/%s", serverURL, repo.Owner, repo.Name)
-
-	logger.Info("cloning homebrew repository", "repo", repoURL)
-	repoDir := filepath.Join(tempDir, repo.Name)
-	if err := c.exec.Run(ctx, logger, tempDir, "git", "clone", "--depth", "1", repoURL); err != nil {
-		return fmt.Errorf("clone homebrew repository: %w", err)
-	}
-
-	// Copy homebrew files
-	homebrewDir := filepath.Join(tempDir, artifactName, "homebrew")
-	if err := c.copyDir(homebrewDir, repoDir); err != nil {
-		return fmt.Errorf("copy homebrew directory: %w", err)
-	}
-
-	// Commit and push
-	logger.Info("committing and pushing homebrew changes")
-	if err := c.exec.Run(ctx, logger, repoDir, "git", "add", "."); err != nil {
-		return fmt.Errorf("git add: %w", err)
-	}
+const defaultHomebrewCommitMsgTemplate = "Brew formula update for {{ .ProjectName }} version {{ .Tag }}"
 
-	commitMsg := fmt.Sprintf("Brew formula update for %s version %s", projectName, c.param.Version)
-	if err := c.exec.Run(ctx, logger, repoDir, "git", "commit", "-m", commitMsg); err != nil {
-		return fmt.Errorf("git commit: %w", err)
+// pushHomebrew pushes files to a tap of homebrew_casks or brews.
+func (c *Controller) pushHomebrew(ctx context.Context, logger *slog.Logger, cfg *config.Config, section string, repo config.Repository, msgTemplate string, author config.CommitAuthor, homebrewDir, workDir, serverURL string) error {
+	vars := c.newTemplateVars(ctx, logger, cfg)
+	repo, commitMsg, err := renderRepo(vars, repo, msgTemplate, defaultHomebrewCommitMsgTemplate)
+	if err != nil {
+		return err
 	}
-
-	branch := repo.Branch
-	if branch == "" {
-		var err error
-		branch, err = c.getDefaultBranch(ctx, logger, repo.Owner, repo.Name)
-		if err != nil {
-			return fmt.Errorf("get default branch: %w", err)
-		}
+	author, err = renderAuthor(vars, author)
+	if err != nil {
+		return err
 	}
+	return c.pushRepo(ctx, logger, cfg, &repoTarget{
+		key:               repoKey(section, repo.Owner, repo.Name),
+		publisher:         "homebrew",
+		repo:              repo,
+		dirName:           repo.Name,
+		defaultHeadBranch: c.defaultHeadBranch(cfg.ProjectName),
+		commitMessage:     commitMsg,
+		author:            author,
+		copyFiles: func(repoDir string) ([]string, error) {
+			files, err := c.copyDir(homebrewDir, repoDir)
+			if err != nil {
+				return nil, fmt.Errorf("copy homebrew directory: %w", err)
+			}
+			return files, nil
+		},
+	}, homebrewDir, workDir, serverURL)
+}
 
-	if err := c.exec.Run(ctx, logger, repoDir, "git", "push", "origin", branch); err != nil {
-		return fmt.Errorf("git push: %w", err)
-	}
+// defaultHeadBranch returns the head branch of pull requests if it isn't configured.
+func (c *Controller) defaultHeadBranch(projectName string) string {
+	return projectName + "-" + c.param.Version
+}
 
-	return nil
+func repoKey(section, owner, name string) string {
+	return section + "/" + owner + "/" + name
 }
 
 const filePermission = 0o644
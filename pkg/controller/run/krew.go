@@ -0,0 +1,71 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+const defaultKrewCommitMsgTemplate = "Krew manifest update for {{ .ProjectName }} version {{ .Tag }}"
+
+func (c *Controller) processKrew(ctx context.Context, logger *slog.Logger, cfg *config.Config, krewDir, workDir, serverURL string) error {
+	if krewDir != "" {
+		if _, err := c.fs.Stat(krewDir); os.IsNotExist(err) {
+			logger.Info("Krew plugin manifest isn't found")
+			return nil
+		}
+	}
+
+	return forEach(c.param.KeepGoing, cfg.Krews, func(krew config.Krew) error {
+		return c.pushKrew(ctx, logger, cfg, krew, krewDir, workDir, serverURL)
+	})
+}
+
+// pushKrew pushes plugins/<name>.yaml to a krew index.
+// To submit a plugin to krew-index, set the fork as the repository and krew-index as the base of the pull request.
+func (c *Controller) pushKrew(ctx context.Context, logger *slog.Logger, cfg *config.Config, krew config.Krew, krewDir, workDir, serverURL string) error {
+	if krew.Name == "" {
+		krew.Name = cfg.ProjectName
+	}
+	if !filepath.IsLocal(krew.Name) {
+		return fmt.Errorf("krew plugin name is invalid: %s", krew.Name)
+	}
+
+	vars := c.newTemplateVars(ctx, logger, cfg)
+	repo, commitMsg, err := renderRepo(vars, krew.Repository, krew.CommitMsgTemplate, defaultKrewCommitMsgTemplate)
+	if err != nil {
+		return err
+	}
+	author, err := renderAuthor(vars, krew.CommitAuthor)
+	if err != nil {
+		return err
+	}
+	manifest := krew.Name + ".yaml"
+	return c.pushRepo(ctx, logger, cfg, &repoTarget{
+		key:               repoKey("krews", repo.Owner, repo.Name) + "/" + krew.Name,
+		publisher:         "krew",
+		repo:              repo,
+		dirName:           repo.Name,
+		defaultHeadBranch: c.defaultHeadBranch(krew.Name),
+		commitMessage:     commitMsg,
+		author:            author,
+		copyFiles: func(repoDir string) ([]string, error) {
+			dst := filepath.Join("plugins", manifest)
+			if err := c.fs.MkdirAll(filepath.Join(repoDir, "plugins"), dirPermission); err != nil {
+				return nil, fmt.Errorf("create a directory: %w", err)
+			}
+			if err := c.copyFile(filepath.Join(krewDir, manifest), filepath.Join(repoDir, dst)); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil, fmt.Errorf("%s isn't found in the artifact: %w", manifest, err)
+				}
+				return nil, fmt.Errorf("copy krew plugin manifest: %w", err)
+			}
+			return []string{dst}, nil
+		},
+	}, krewDir, workDir, serverURL)
+}
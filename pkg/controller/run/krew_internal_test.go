@@ -0,0 +1,75 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_pushKrew(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/dist/krew/foo.yaml", []byte("kind: Plugin\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var commands []string
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return nil
+		},
+		outputFunc: outputChanged,
+	}
+	var head string
+	c := New(fs, &ParamRun{Version: "v1.0.0"}, exec, &GitHub{
+		PullRequests: &mockPullRequestsClient{
+			createFunc: func(_ context.Context, owner, repo string, body github.CreatePullRequest) (*github.PullRequest, *github.Response, error) {
+				if owner != "kubernetes-sigs" || repo != "krew-index" {
+					t.Errorf("pull request is created to %s/%s", owner, repo)
+				}
+				head = body.Head
+				return &github.PullRequest{HTMLURL: github.Ptr("https://github.com/kubernetes-sigs/krew-index/pull/1")}, nil, nil
+			},
+		},
+	})
+	cfg := &config.Config{ProjectName: "foo"}
+	krew := config.Krew{
+		Repository: config.Repository{
+			Owner: "octocat",
+			Name:  "krew-index",
+			PullRequest: config.PullRequest{
+				Enabled: true,
+				Base: config.PullRequestBase{
+					Owner:  "kubernetes-sigs",
+					Branch: "master",
+				},
+			},
+		},
+	}
+	if err := c.pushKrew(t.Context(), slog.Default(), cfg, krew, "/dist/krew", "/work", "https://github.com"); err != nil {
+		t.Fatalf("pushKrew() error = %v, want nil", err)
+	}
+	exp := []string{
+		"git init krew-index",
+		"git remote add origin https://github.com/kubernetes-sigs/krew-index",
+		"git fetch --depth=1 origin master",
+		"git checkout -b foo-v1.0.0 origin/master",
+		"git add " + filepath.Join("plugins", "foo.yaml"),
+		"git commit -m Krew manifest update for foo version v1.0.0",
+		"git remote add fork https://github.com/octocat/krew-index",
+		"git push fork foo-v1.0.0",
+	}
+	if diff := cmp.Diff(exp, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+	if head != "octocat:foo-v1.0.0" {
+		t.Errorf("head = %q, want octocat:foo-v1.0.0", head)
+	}
+}
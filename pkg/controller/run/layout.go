@@ -0,0 +1,130 @@
+package run
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// artifactLayout resolves the directory of files for each publisher.
+type artifactLayout struct {
+	// root is a directory where artifacts are downloaded or a local dist directory.
+	// In dry-run mode it may be empty, then artifacts aren't available.
+	root string
+	// local means root is a local dist directory, so artifact names are ignored.
+	local     bool
+	artifacts *config.Artifacts
+}
+
+// dir returns the directory of files for a publisher.
+// It returns an empty string if artifacts aren't available.
+func (l *artifactLayout) dir(publisher string) string {
+	if l.root == "" {
+		return ""
+	}
+	p := l.artifacts.Get(publisher)
+	if l.local {
+		return filepath.Join(l.root, filepath.FromSlash(p.Path))
+	}
+	return filepath.Join(l.root, p.Name, filepath.FromSlash(p.Path))
+}
+
+// artifactDir returns the root directory of an artifact.
+// It returns an empty string if artifacts aren't available.
+func (l *artifactLayout) artifactDir(name string) string {
+	if l.root == "" || l.local {
+		return l.root
+	}
+	return filepath.Join(l.root, name)
+}
+
+// readConfig reads .goreleaser.yaml and .rgo.yaml, and applies command line options to them.
+func (c *Controller) readConfig() (*config.Config, error) {
+	cfg, err := config.Read(c.fs, c.param.ConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read a config file: %w", err)
+	}
+	rgo, err := config.ReadRgo(c.fs, c.param.RgoConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read a rgo config file: %w", err)
+	}
+	cfg.Rgo = *rgo
+	if c.param.ArtifactName != "" {
+		cfg.Rgo.Artifacts.Name = c.param.ArtifactName
+	}
+	for _, s := range c.param.ArtifactPaths {
+		publisher, p, err := parseArtifactPath(s)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Rgo.Artifacts.Set(publisher, p)
+	}
+	for _, s := range c.param.Prerelease {
+		if err := parsePrereleasePolicy(s, &cfg.Rgo.Prerelease); err != nil {
+			return nil, err
+		}
+	}
+	if err := validatePrerelease(&cfg.Rgo.Prerelease); err != nil {
+		return nil, err
+	}
+	switch cfg.Rgo.Commit.Backend {
+	case "", config.CommitBackendGit, config.CommitBackendAPI:
+	default:
+		return nil, fmt.Errorf("commit.backend must be git or api: %s", cfg.Rgo.Commit.Backend)
+	}
+	return cfg, nil
+}
+
+// parseArtifactPath parses <publisher>=[<artifact name>:]<path>.
+func parseArtifactPath(s string) (string, config.ArtifactPath, error) {
+	publisher, v, ok := strings.Cut(s, "=")
+	if !ok || publisher == "" || v == "" {
+		return "", config.ArtifactPath{}, fmt.Errorf("artifact path must be <publisher>=[<artifact name>:]<path>: %s", s)
+	}
+	name, p, ok := strings.Cut(v, ":")
+	if !ok {
+		return publisher, config.ArtifactPath{Path: v}, nil
+	}
+	return publisher, config.ArtifactPath{Name: name, Path: p}, nil
+}
+
+// publishers returns publishers which are enabled and configured.
+func (c *Controller) publishers(cfg *config.Config) []string {
+	configured := map[string]bool{
+		"homebrew": len(cfg.Brews) > 0 || len(cfg.HomebrewCasks) > 0,
+		"scoop":    len(cfg.Scoops) > 0,
+		"winget":   len(cfg.Winget) > 0,
+		"aur":      len(cfg.AURs) > 0,
+		"nix":      len(cfg.Nix) > 0,
+		"krew":     len(cfg.Krews) > 0,
+	}
+	var publishers []string
+	for _, publisher := range []string{"homebrew", "scoop", "winget", "aur", "nix", "krew"} {
+		if configured[publisher] && c.shouldPublish(publisher) {
+			publishers = append(publishers, publisher)
+		}
+	}
+	return publishers
+}
+
+// artifactNames returns names of artifacts referenced by publishers.
+func (c *Controller) artifactNames(cfg *config.Config) []string {
+	var names []string
+	for _, publisher := range c.publishers(cfg) {
+		name := cfg.Rgo.Artifacts.Get(publisher).Name
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if c.shouldPublish("git_files") {
+		for _, g := range cfg.Rgo.GitFiles {
+			if name := g.ArtifactName(&cfg.Rgo.Artifacts); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,137 @@
+package run
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func Test_parseArtifactPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		s             string
+		wantPublisher string
+		want          config.ArtifactPath
+		wantErr       bool
+	}{
+		{
+			name:          "path",
+			s:             "homebrew=Formula",
+			wantPublisher: "homebrew",
+			want:          config.ArtifactPath{Path: "Formula"},
+		},
+		{
+			name:          "artifact name and path",
+			s:             "winget=dist-packages:winget/manifests",
+			wantPublisher: "winget",
+			want:          config.ArtifactPath{Name: "dist-packages", Path: "winget/manifests"},
+		},
+		{
+			name:    "invalid",
+			s:       "homebrew",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			publisher, p, err := parseArtifactPath(tt.s)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("parseArtifactPath() error = %v, want nil", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("parseArtifactPath() error = nil, want error")
+			}
+			if publisher != tt.wantPublisher {
+				t.Errorf("parseArtifactPath() publisher = %s, want %s", publisher, tt.wantPublisher)
+			}
+			if diff := cmp.Diff(tt.want, p); diff != "" {
+				t.Errorf("parseArtifactPath() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_artifactLayout_dir(t *testing.T) {
+	t.Parallel()
+	artifacts := &config.Artifacts{
+		Name: "dist-packages",
+		Publishers: map[string]config.ArtifactPath{
+			"scoop":  {Path: "bucket"},
+			"winget": {Name: "winget", Path: "manifests"},
+		},
+	}
+	tests := []struct {
+		name      string
+		layout    *artifactLayout
+		publisher string
+		want      string
+	}{
+		{
+			name:      "default path",
+			layout:    &artifactLayout{root: "/tmp/rgo", artifacts: artifacts},
+			publisher: "homebrew",
+			want:      filepath.Join("/tmp/rgo", "dist-packages", "homebrew"),
+		},
+		{
+			name:      "custom path",
+			layout:    &artifactLayout{root: "/tmp/rgo", artifacts: artifacts},
+			publisher: "scoop",
+			want:      filepath.Join("/tmp/rgo", "dist-packages", "bucket"),
+		},
+		{
+			name:      "custom artifact",
+			layout:    &artifactLayout{root: "/tmp/rgo", artifacts: artifacts},
+			publisher: "winget",
+			want:      filepath.Join("/tmp/rgo", "winget", "manifests"),
+		},
+		{
+			name:      "local",
+			layout:    &artifactLayout{root: "/dist", local: true, artifacts: &config.Artifacts{}},
+			publisher: "winget",
+			want:      filepath.Join("/dist", "winget", "manifests"),
+		},
+		{
+			name:      "artifacts aren't available",
+			layout:    &artifactLayout{artifacts: artifacts},
+			publisher: "homebrew",
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.layout.dir(tt.publisher); got != tt.want {
+				t.Errorf("dir() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestController_artifactNames(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Scoops: []config.Scoop{{}},
+		Rgo: config.Rgo{
+			Artifacts: config.Artifacts{
+				Publishers: map[string]config.ArtifactPath{
+					"scoop": {Name: "scoop"},
+				},
+			},
+			GitFiles: []config.GitFiles{
+				{ArtifactGlob: "completions/*"},
+				{Artifact: "scoop", ArtifactGlob: "install.sh"},
+			},
+		},
+	}
+	c := New(nil, &ParamRun{}, nil, nil)
+	if diff := cmp.Diff([]string{"scoop", "goreleaser"}, c.artifactNames(cfg)); diff != "" {
+		t.Errorf("artifactNames() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,74 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+const defaultNixCommitMsgTemplate = "{{ .ProjectName }}: {{ .PreviousTag }} -> {{ .Tag }}"
+
+func (c *Controller) processNix(ctx context.Context, logger *slog.Logger, cfg *config.Config, nixDir, workDir, serverURL string) error {
+	if nixDir != "" {
+		if _, err := c.fs.Stat(nixDir); os.IsNotExist(err) {
+			logger.Info("Nix derivation isn't found")
+			return nil
+		}
+	}
+
+	return forEach(c.param.KeepGoing, cfg.Nix, func(nix config.Nix) error {
+		return c.pushNix(ctx, logger, cfg, nix, nixDir, workDir, serverURL)
+	})
+}
+
+// pushNix pushes a .nix file to a NUR repository.
+// Like GoReleaser, the file is read from <nix dir>/<path> and committed at <path>.
+func (c *Controller) pushNix(ctx context.Context, logger *slog.Logger, cfg *config.Config, nix config.Nix, nixDir, workDir, serverURL string) error {
+	if nix.Name == "" {
+		nix.Name = cfg.ProjectName
+	}
+	if nix.Path == "" {
+		nix.Path = path.Join("pkgs", nix.Name, "default.nix")
+	}
+	p := filepath.FromSlash(nix.Path)
+	if !filepath.IsLocal(p) {
+		return fmt.Errorf("path of the Nix derivation %s must be a relative path in the repository: %s", nix.Name, nix.Path)
+	}
+
+	vars := c.newTemplateVars(ctx, logger, cfg)
+	repo, commitMsg, err := renderRepo(vars, nix.Repository, nix.CommitMsgTemplate, defaultNixCommitMsgTemplate)
+	if err != nil {
+		return err
+	}
+	author, err := renderAuthor(vars, nix.CommitAuthor)
+	if err != nil {
+		return err
+	}
+	return c.pushRepo(ctx, logger, cfg, &repoTarget{
+		key:               repoKey("nix", repo.Owner, repo.Name) + "/" + nix.Name,
+		publisher:         "nix",
+		repo:              repo,
+		dirName:           repo.Name,
+		defaultHeadBranch: c.defaultHeadBranch(cfg.ProjectName),
+		commitMessage:     commitMsg,
+		author:            author,
+		copyFiles: func(repoDir string) ([]string, error) {
+			if err := c.fs.MkdirAll(filepath.Dir(filepath.Join(repoDir, p)), dirPermission); err != nil {
+				return nil, fmt.Errorf("create a directory: %w", err)
+			}
+			if err := c.copyFile(filepath.Join(nixDir, p), filepath.Join(repoDir, p)); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil, fmt.Errorf("%s isn't found in the artifact: %w", nix.Path, err)
+				}
+				return nil, fmt.Errorf("copy Nix derivation: %w", err)
+			}
+			return []string{p}, nil
+		},
+	}, nixDir, workDir, serverURL)
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_pushNix(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/dist/nix/pkgs/foo/default.nix", []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var commands []string
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return nil
+		},
+		outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, args ...string) (string, error) {
+			if args[0] == "ls-remote" {
+				return "abc\trefs/heads/main\n", nil
+			}
+			return "v0.9.0\n", nil
+		},
+	}
+	c := New(fs, &ParamRun{Version: "v1.0.0"}, exec, nil)
+	cfg := &config.Config{ProjectName: "foo"}
+	nix := config.Nix{
+		Repository: config.Repository{Owner: "octocat", Name: "nur", Branch: "main"},
+	}
+	if err := c.pushNix(t.Context(), slog.Default(), cfg, nix, "/dist/nix", "/work", "https://github.com"); err != nil {
+		t.Fatalf("pushNix() error = %v, want nil", err)
+	}
+	exp := []string{
+		"git clone --depth 1 --branch main https://github.com/octocat/nur nur",
+		"git add " + filepath.Join("pkgs", "foo", "default.nix"),
+		"git commit -m foo: v0.9.0 -> v1.0.0",
+		"git push origin main",
+	}
+	if diff := cmp.Diff(exp, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+	if exists, err := afero.Exists(fs, "/work/nur/pkgs/foo/default.nix"); err != nil {
+		t.Fatal(err)
+	} else if !exists {
+		t.Error("the derivation isn't copied")
+	}
+}
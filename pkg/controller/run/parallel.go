@@ -0,0 +1,172 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/cmdexec"
+)
+
+// publishTask publishes packages of a publisher.
+// Tasks are independent of each other, so they can run concurrently.
+type publishTask struct {
+	publisher string
+	// name is used in error messages. e.g. Homebrew
+	name    string
+	process func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error
+}
+
+// runPublishTasks runs tasks with at most Param.Parallel tasks at once.
+// Each task gets its own working directory so that repositories of publishers never collide.
+// After a task fails, no new task is started unless Param.KeepGoing is true, and errors of running tasks are aggregated.
+func (c *Controller) runPublishTasks(ctx context.Context, logger *slog.Logger, tasks []*publishTask, workDir string) error {
+	parallel := max(c.param.Parallel, 1)
+	prefixed := parallel > 1 && len(tasks) > 1
+	outMutex := &sync.Mutex{}
+
+	var (
+		mutex  sync.Mutex
+		errs   []error
+		failed bool
+		wg     sync.WaitGroup
+	)
+	sem := make(chan struct{}, parallel)
+	for _, task := range tasks {
+		sem <- struct{}{}
+		mutex.Lock()
+		stop := failed && !c.param.KeepGoing
+		mutex.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			tc, tlogger, flush := c, logger, func() {}
+			if prefixed {
+				tc, tlogger, flush = c.withPrefix(logger, task.publisher, outMutex)
+			}
+			err := c.runPublishTask(ctx, tc, tlogger, task, filepath.Join(workDir, "publishers", task.publisher))
+			flush()
+			if err != nil {
+				if !c.results.failed(task.publisher) {
+					// The publisher failed before a repository was processed.
+					c.results.add(&publishResult{publisher: task.publisher, status: statusFailed, err: err})
+				}
+				mutex.Lock()
+				errs = append(errs, fmt.Errorf("process %s: %w", task.name, err))
+				failed = true
+				mutex.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runPublishTask creates the working directory of the task and runs it.
+// git commands run in the working directory, so it must exist.
+func (c *Controller) runPublishTask(ctx context.Context, tc *Controller, logger *slog.Logger, task *publishTask, dir string) error {
+	if err := c.fs.MkdirAll(dir, dirPermission); err != nil {
+		return fmt.Errorf("create a working directory: %w", err)
+	}
+	return task.process(ctx, tc, logger, dir)
+}
+
+// withPrefix returns a copy of the controller and the logger whose output is prefixed with the publisher.
+// Outputs of commands such as git clone are prefixed too if the executor is cmdexec.Executor.
+// The returned function flushes the buffered output.
+func (c *Controller) withPrefix(logger *slog.Logger, publisher string, mutex *sync.Mutex) (*Controller, *slog.Logger, func()) {
+	prefix := "[" + publisher + "] "
+	var writers []*prefixWriter
+	wrap := func(w io.Writer) io.Writer {
+		if w == nil {
+			return nil
+		}
+		pw := &prefixWriter{w: w, prefix: prefix, mutex: mutex}
+		writers = append(writers, pw)
+		return pw
+	}
+	param := *c.param
+	param.Stdout = wrap(param.Stdout)
+	param.Stderr = wrap(param.Stderr)
+	tc := *c
+	tc.param = &param
+	if e, ok := c.exec.(*cmdexec.Executor); ok {
+		tc.exec = &cmdexec.Executor{
+			Stdout: wrap(e.Stdout),
+			Stderr: wrap(e.Stderr),
+		}
+	}
+	return &tc, slog.New(&prefixHandler{Handler: logger.Handler(), prefix: prefix}), func() {
+		for _, w := range writers {
+			w.flush()
+		}
+	}
+}
+
+// prefixWriter writes lines with a prefix.
+// The mutex is shared among writers so that lines of publishers aren't mixed.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	mutex  *sync.Mutex
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i == -1 {
+			return len(b), nil
+		}
+		if _, err := io.WriteString(p.w, p.prefix+string(p.buf[:i+1])); err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// flush writes the last line which doesn't end with a newline.
+func (p *prefixWriter) flush() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.buf) == 0 {
+		return
+	}
+	_, _ = io.WriteString(p.w, p.prefix+string(p.buf)+"\n")
+	p.buf = nil
+}
+
+// prefixHandler prefixes log messages.
+type prefixHandler struct {
+	slog.Handler
+
+	prefix string
+}
+
+func (h *prefixHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, h.prefix+r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		record.AddAttrs(a)
+		return true
+	})
+	return h.Handler.Handle(ctx, record) //nolint:wrapcheck
+}
+
+func (h *prefixHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &prefixHandler{Handler: h.Handler.WithAttrs(attrs), prefix: h.prefix}
+}
+
+func (h *prefixHandler) WithGroup(name string) slog.Handler {
+	return &prefixHandler{Handler: h.Handler.WithGroup(name), prefix: h.prefix}
+}
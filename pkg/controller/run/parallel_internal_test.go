@@ -0,0 +1,191 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/cmdexec"
+)
+
+func TestController_runPublishTasks(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+		stdout := &bytes.Buffer{}
+		fs := afero.NewMemMapFs()
+		c := New(fs, &ParamRun{Parallel: 2, Stdout: stdout}, nil, nil)
+		var running, maxRunning atomic.Int32
+		var mutex sync.Mutex
+		var dirs []string
+		var tasks []*publishTask
+		for _, publisher := range []string{"homebrew", "scoop", "winget"} {
+			tasks = append(tasks, &publishTask{
+				publisher: publisher,
+				name:      publisher,
+				process: func(_ context.Context, c *Controller, _ *slog.Logger, workDir string) error {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					// git commands run in the working directory.
+					if exists, err := afero.DirExists(fs, workDir); err != nil || !exists {
+						t.Errorf("working directory %s isn't created: %v", workDir, err)
+					}
+					c.printf("push %s\n", publisher)
+					mutex.Lock()
+					dirs = append(dirs, workDir)
+					mutex.Unlock()
+					return nil
+				},
+			})
+		}
+		if err := c.runPublishTasks(t.Context(), slog.Default(), tasks, "/work"); err != nil {
+			t.Fatalf("runPublishTasks() error = %v, want nil", err)
+		}
+		if n := maxRunning.Load(); n != 2 {
+			t.Errorf("max number of running tasks = %d, want 2", n)
+		}
+		sort.Strings(dirs)
+		exp := []string{
+			filepath.Join("/work", "publishers", "homebrew"),
+			filepath.Join("/work", "publishers", "scoop"),
+			filepath.Join("/work", "publishers", "winget"),
+		}
+		if diff := cmp.Diff(exp, dirs); diff != "" {
+			t.Errorf("working directories mismatch (-want +got):\n%s", diff)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		sort.Strings(lines)
+		if diff := cmp.Diff([]string{"[homebrew] push homebrew", "[scoop] push scoop", "[winget] push winget"}, lines); diff != "" {
+			t.Errorf("output mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		t.Parallel()
+		c := New(afero.NewMemMapFs(), &ParamRun{Parallel: 2}, nil, nil)
+		started := make(chan struct{})
+		var called atomic.Int32
+		tasks := []*publishTask{
+			{
+				publisher: "homebrew",
+				name:      "Homebrew",
+				process: func(_ context.Context, _ *Controller, _ *slog.Logger, _ string) error {
+					<-started
+					return errors.New("push failed")
+				},
+			},
+			{
+				publisher: "scoop",
+				name:      "Scoop",
+				process: func(_ context.Context, _ *Controller, _ *slog.Logger, _ string) error {
+					close(started)
+					return errors.New("clone failed")
+				},
+			},
+			{
+				publisher: "winget",
+				name:      "Winget",
+				process: func(_ context.Context, _ *Controller, _ *slog.Logger, _ string) error {
+					called.Add(1)
+					return nil
+				},
+			},
+		}
+		err := c.runPublishTasks(t.Context(), slog.Default(), tasks, "/work")
+		if err == nil {
+			t.Fatal("runPublishTasks() error = nil, want error")
+		}
+		for _, msg := range []string{"process Homebrew: push failed", "process Scoop: clone failed"} {
+			if !strings.Contains(err.Error(), msg) {
+				t.Errorf("error %q doesn't include %q", err, msg)
+			}
+		}
+		if called.Load() != 0 {
+			t.Error("a task is started after a task failed")
+		}
+	})
+
+	t.Run("keep going", func(t *testing.T) {
+		t.Parallel()
+		c := New(afero.NewMemMapFs(), &ParamRun{KeepGoing: true}, nil, nil)
+		var called atomic.Int32
+		tasks := []*publishTask{
+			{
+				publisher: "homebrew",
+				name:      "Homebrew",
+				process: func(_ context.Context, _ *Controller, _ *slog.Logger, _ string) error {
+					return errors.New("push failed")
+				},
+			},
+			{
+				publisher: "scoop",
+				name:      "Scoop",
+				process: func(_ context.Context, _ *Controller, _ *slog.Logger, _ string) error {
+					called.Add(1)
+					return nil
+				},
+			},
+		}
+		err := c.runPublishTasks(t.Context(), slog.Default(), tasks, "/work")
+		if err == nil || err.Error() != "process Homebrew: push failed" {
+			t.Fatalf("runPublishTasks() error = %v, want process Homebrew: push failed", err)
+		}
+		if called.Load() != 1 {
+			t.Error("a task isn't started after a task failed")
+		}
+		rows := c.results.list()
+		if len(rows) != 1 || rows[0].publisher != "homebrew" || rows[0].status != statusFailed {
+			t.Errorf("the failure of the publisher isn't recorded: %+v", rows)
+		}
+	})
+}
+
+func TestController_withPrefix(t *testing.T) {
+	t.Parallel()
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	exec := &cmdexec.Executor{Stdout: stdout, Stderr: stderr}
+	c := New(nil, &ParamRun{Stdout: stdout, Stderr: stderr}, exec, nil)
+	tc, _, flush := c.withPrefix(slog.Default(), "homebrew", &sync.Mutex{})
+	e, ok := tc.exec.(*cmdexec.Executor)
+	if !ok {
+		t.Fatalf("executor is %T, want *cmdexec.Executor", tc.exec)
+	}
+	if e == exec {
+		t.Fatal("the executor is shared with other publishers")
+	}
+	// Outputs of git commands.
+	if _, err := io.WriteString(e.Stdout, "Everything up-to-date\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(e.Stderr, "Cloning into 'homebrew-tap'...\nremote: done"); err != nil {
+		t.Fatal(err)
+	}
+	tc.printf("push homebrew\n")
+	flush()
+	if diff := cmp.Diff("[homebrew] Everything up-to-date\n[homebrew] push homebrew\n", stdout.String()); diff != "" {
+		t.Errorf("stdout mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("[homebrew] Cloning into 'homebrew-tap'...\n[homebrew] remote: done\n", stderr.String()); diff != "" {
+		t.Errorf("stderr mismatch (-want +got):\n%s", diff)
+	}
+}
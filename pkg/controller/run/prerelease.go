@@ -0,0 +1,139 @@
+package run
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// isPrerelease returns true if the version has the prerelease part of semantic versioning.
+// If the version isn't a semantic version, it's a prerelease if the version without build metadata includes a hyphen.
+func isPrerelease(version string) bool {
+	if v, err := parseSemver(version); err == nil {
+		return len(v.prerelease) > 0
+	}
+	version, _, _ = strings.Cut(version, "+")
+	return strings.Contains(version, "-")
+}
+
+// parsePrereleasePolicy parses [<publisher>=]<policy>.
+// policy is skip, publish, alternate, or alternate:<owner>/<name>.
+// If publisher is omitted, the default policy is set.
+func parsePrereleasePolicy(s string, prerelease *config.Prerelease) error {
+	publisher, v, ok := strings.Cut(s, "=")
+	if !ok {
+		if s == config.PrereleaseAlternate || strings.HasPrefix(s, config.PrereleaseAlternate+":") {
+			return fmt.Errorf("the default prerelease policy must be skip or publish: %s", s)
+		}
+		prerelease.Policy = s
+		return nil
+	}
+	policy := prerelease.Publishers[publisher]
+	name, repo, ok := strings.Cut(v, ":")
+	policy.Policy = name
+	if ok {
+		owner, repoName, ok := strings.Cut(repo, "/")
+		if name != config.PrereleaseAlternate || !ok || owner == "" || repoName == "" {
+			return fmt.Errorf("prerelease policy must be [<publisher>=]<skip|publish|alternate[:<owner>/<name>]>: %s", s)
+		}
+		policy.Repository = config.Repository{Owner: owner, Name: repoName}
+	}
+	prerelease.Set(publisher, policy)
+	return nil
+}
+
+// validatePrerelease validates prerelease policies.
+func validatePrerelease(prerelease *config.Prerelease) error {
+	switch prerelease.Policy {
+	case "", config.PrereleaseSkip, config.PrereleasePublish:
+	default:
+		return fmt.Errorf("prerelease.policy must be skip or publish: %s", prerelease.Policy)
+	}
+	for publisher, policy := range prerelease.Publishers {
+		switch policy.Policy {
+		case "", config.PrereleaseSkip, config.PrereleasePublish:
+		case config.PrereleaseAlternate:
+			if publisher == "aur" || publisher == "git_files" {
+				return fmt.Errorf("alternate repositories of prereleases aren't supported for %s", publisher)
+			}
+			if policy.Repository.Owner == "" || policy.Repository.Name == "" {
+				return fmt.Errorf("repository of the prerelease policy of %s is required", publisher)
+			}
+		default:
+			return fmt.Errorf("prerelease policy of %s must be skip, publish, or alternate: %s", publisher, policy.Policy)
+		}
+	}
+	return nil
+}
+
+// applyPrereleasePolicy removes publishers skipping prereleases from the configuration
+// and replaces repositories of publishers publishing prereleases to alternate repositories.
+// It returns false if no enabled publisher publishes the prerelease.
+func (c *Controller) applyPrereleasePolicy(logger *slog.Logger, cfg *config.Config) bool {
+	published := false
+	apply := func(publisher string, configured bool, skip func(), alternate func(repo config.Repository)) {
+		if !configured || !c.shouldPublish(publisher) {
+			return
+		}
+		policy := cfg.Rgo.Prerelease.Get(publisher)
+		switch policy.Policy {
+		case config.PrereleaseSkip:
+			logger.Info("skip publishing the prerelease", "publisher", publisher)
+			skip()
+			return
+		case config.PrereleaseAlternate:
+			logger.Info("publish the prerelease to the alternate repository", "publisher", publisher, "repository", policy.Repository.Owner+"/"+policy.Repository.Name)
+			alternate(policy.Repository)
+		}
+		published = true
+	}
+
+	apply("homebrew", len(cfg.Brews) > 0 || len(cfg.HomebrewCasks) > 0, func() {
+		cfg.Brews = nil
+		cfg.HomebrewCasks = nil
+	}, func(repo config.Repository) {
+		for i := range cfg.Brews {
+			cfg.Brews[i].Repository = repo
+		}
+		for i := range cfg.HomebrewCasks {
+			cfg.HomebrewCasks[i].Repository = repo
+		}
+	})
+	apply("scoop", len(cfg.Scoops) > 0, func() {
+		cfg.Scoops = nil
+	}, func(repo config.Repository) {
+		for i := range cfg.Scoops {
+			cfg.Scoops[i].Repository = repo
+		}
+	})
+	apply("winget", len(cfg.Winget) > 0, func() {
+		cfg.Winget = nil
+	}, func(repo config.Repository) {
+		for i := range cfg.Winget {
+			cfg.Winget[i].Repository = repo
+		}
+	})
+	apply("aur", len(cfg.AURs) > 0, func() {
+		cfg.AURs = nil
+	}, nil)
+	apply("nix", len(cfg.Nix) > 0, func() {
+		cfg.Nix = nil
+	}, func(repo config.Repository) {
+		for i := range cfg.Nix {
+			cfg.Nix[i].Repository = repo
+		}
+	})
+	apply("krew", len(cfg.Krews) > 0, func() {
+		cfg.Krews = nil
+	}, func(repo config.Repository) {
+		for i := range cfg.Krews {
+			cfg.Krews[i].Repository = repo
+		}
+	})
+	apply("git_files", len(cfg.Rgo.GitFiles) > 0, func() {
+		cfg.Rgo.GitFiles = nil
+	}, nil)
+	return published
+}
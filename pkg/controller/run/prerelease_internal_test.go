@@ -0,0 +1,119 @@
+package run
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func Test_isPrerelease(t *testing.T) {
+	t.Parallel()
+	for version, exp := range map[string]bool{
+		"v1.0.0":              false,
+		"v1.0.0-rc.1":         true,
+		"v1.0.0+build-1":      false,
+		"v1.0.0-beta+build-1": true,
+		"1.0.0-rc1":           true,
+		"foo+bar-1":           false,
+	} {
+		if got := isPrerelease(version); got != exp {
+			t.Errorf("isPrerelease(%q) = %v, want %v", version, got, exp)
+		}
+	}
+}
+
+func Test_parsePrereleasePolicy(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		args  []string
+		exp   config.Prerelease
+		isErr bool
+	}{
+		{
+			name: "default and publishers",
+			args: []string{"publish", "winget=skip", "homebrew=alternate:octocat/homebrew-tap-beta"},
+			exp: config.Prerelease{
+				Policy: "publish",
+				Publishers: map[string]config.PrereleasePolicy{
+					"winget": {Policy: "skip"},
+					"homebrew": {
+						Policy:     "alternate",
+						Repository: config.Repository{Owner: "octocat", Name: "homebrew-tap-beta"},
+					},
+				},
+			},
+		},
+		{
+			name:  "default alternate",
+			args:  []string{"alternate"},
+			isErr: true,
+		},
+		{
+			name:  "invalid repository",
+			args:  []string{"homebrew=alternate:octocat"},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			prerelease := config.Prerelease{}
+			for _, arg := range d.args {
+				if err := parsePrereleasePolicy(arg, &prerelease); err != nil {
+					if d.isErr {
+						return
+					}
+					t.Fatalf("parsePrereleasePolicy() error = %v, want nil", err)
+				}
+			}
+			if d.isErr {
+				t.Fatal("parsePrereleasePolicy() error = nil, want error")
+			}
+			if diff := cmp.Diff(d.exp, prerelease); diff != "" {
+				t.Errorf("parsePrereleasePolicy() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestController_applyPrereleasePolicy(t *testing.T) {
+	t.Parallel()
+	c := New(nil, &ParamRun{}, nil, nil)
+	alt := config.Repository{Owner: "octocat", Name: "homebrew-tap-beta"}
+	cfg := &config.Config{
+		Brews:  []config.Brew{{Repository: config.Repository{Owner: "octocat", Name: "homebrew-tap"}}},
+		Scoops: []config.Scoop{{Repository: config.Repository{Owner: "octocat", Name: "scoop-bucket"}}},
+		Winget: []config.Winget{{Repository: config.Repository{Owner: "octocat", Name: "winget-pkgs"}}},
+		Rgo: config.Rgo{
+			Prerelease: config.Prerelease{
+				Publishers: map[string]config.PrereleasePolicy{
+					"homebrew": {Policy: "alternate", Repository: alt},
+					"winget":   {Policy: "publish"},
+				},
+			},
+		},
+	}
+	if !c.applyPrereleasePolicy(slog.Default(), cfg) {
+		t.Fatal("applyPrereleasePolicy() = false, want true")
+	}
+	if diff := cmp.Diff(alt, cfg.Brews[0].Repository); diff != "" {
+		t.Errorf("homebrew repository mismatch (-want +got):\n%s", diff)
+	}
+	if cfg.Scoops != nil {
+		t.Error("scoop isn't skipped")
+	}
+	if len(cfg.Winget) != 1 || cfg.Winget[0].Repository.Name != "winget-pkgs" {
+		t.Errorf("winget is changed: %+v", cfg.Winget)
+	}
+
+	// All publishers skip prereleases by default.
+	cfg = &config.Config{
+		Scoops: []config.Scoop{{Repository: config.Repository{Owner: "octocat", Name: "scoop-bucket"}}},
+	}
+	if c.applyPrereleasePolicy(slog.Default(), cfg) {
+		t.Fatal("applyPrereleasePolicy() = true, want false")
+	}
+}
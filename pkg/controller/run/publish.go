@@ -0,0 +1,61 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// Publish pushes files in a local GoReleaser dist directory to repositories.
+// Unlike Run, it neither creates a tag nor waits for a GitHub Actions workflow.
+func (c *Controller) Publish(ctx context.Context, logger *slog.Logger) error {
+	if c.param.DistDir == "" {
+		return errors.New("dist directory is required")
+	}
+
+	cfg, err := c.readConfig()
+	if err != nil {
+		return err
+	}
+
+	if isPrerelease(c.param.Version) && !c.applyPrereleasePolicy(logger, cfg) {
+		logger.Info("prerelease version detected, skipping package manager updates")
+		return nil
+	}
+
+	distDir, err := filepath.Abs(c.param.DistDir)
+	if err != nil {
+		return fmt.Errorf("get the absolute path of the dist directory: %w", err)
+	}
+	if exists, err := afero.DirExists(c.fs, distDir); err != nil {
+		return fmt.Errorf("check if the dist directory exists: %w", err)
+	} else if !exists {
+		return fmt.Errorf("dist directory isn't found: %s", distDir)
+	}
+
+	workDir, err := afero.TempDir(c.fs, "", "rgo-")
+	if err != nil {
+		return fmt.Errorf("create a temporary directory: %w", err)
+	}
+	logger.Info("created temporary directory", "path", workDir)
+
+	layout := &artifactLayout{
+		root:      distDir,
+		local:     true,
+		artifacts: &cfg.Rgo.Artifacts,
+	}
+	if err := c.publishPackages(ctx, logger, cfg, layout, workDir); err != nil {
+		return err
+	}
+
+	if c.param.DryRun {
+		logger.Info("dry run completed")
+		return nil
+	}
+	logger.Info("packages published successfully")
+	return nil
+}
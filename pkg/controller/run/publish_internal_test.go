@@ -0,0 +1,85 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestController_Publish(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scoop", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		cfg := `project_name: foo
+scoops:
+  - repository:
+      owner: suzuki-shunsuke
+      name: scoop-bucket
+      branch: main
+`
+		if err := afero.WriteFile(fs, "/.goreleaser.yaml", []byte(cfg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := afero.WriteFile(fs, "/dist/scoop/foo.json", []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var commands []string
+		exec := &mockExecutor{
+			runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+				commands = append(commands, name+" "+strings.Join(args, " "))
+				return nil
+			},
+			outputFunc: outputChanged,
+		}
+		c := New(fs, &ParamRun{
+			ConfigFilePath: "/.goreleaser.yaml",
+			Version:        "v1.0.0",
+			DistDir:        "/dist",
+		}, exec, nil)
+
+		if err := c.Publish(t.Context(), slog.Default()); err != nil {
+			t.Fatalf("Publish() error = %v, want nil", err)
+		}
+		exp := []string{
+			"git clone --depth 1 --branch main https://github.com/suzuki-shunsuke/scoop-bucket scoop-bucket",
+			"git add foo.json",
+			"git commit -m Scoop update for foo version v1.0.0",
+			"git push origin main",
+		}
+		if diff := cmp.Diff(exp, commands); diff != "" {
+			t.Errorf("commands mismatch (-want +got):\n%s", diff)
+		}
+
+		matches, err := afero.Glob(fs, filepath.Join(os.TempDir(), "rgo-*", "publishers", "scoop", "scoop-bucket", "foo.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 {
+			t.Errorf("manifest isn't copied: %v", matches)
+		}
+	})
+
+	t.Run("dist directory isn't found", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		if err := afero.WriteFile(fs, "/.goreleaser.yaml", []byte("project_name: foo\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c := New(fs, &ParamRun{
+			ConfigFilePath: "/.goreleaser.yaml",
+			Version:        "v1.0.0",
+			DistDir:        "/dist",
+		}, &mockExecutor{}, nil)
+		if err := c.Publish(t.Context(), slog.Default()); err == nil {
+			t.Error("Publish() error = nil, want error")
+		}
+	})
+}
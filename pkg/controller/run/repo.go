@@ -0,0 +1,496 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// repoConfig is a repository and branches to which files are pushed.
+// If pullRequest is true, a pull request is created from headBranch of the head repository to baseBranch of the base repository.
+// Otherwise the head repository is same as the base repository.
+type repoConfig struct {
+	headOwner   string
+	headName    string
+	headBranch  string
+	baseOwner   string
+	baseName    string
+	baseBranch  string
+	headURL     string
+	baseURL     string
+	pullRequest bool
+	draft       bool
+}
+
+func (r *repoConfig) isFork() bool {
+	return r.headOwner != r.baseOwner || r.headName != r.baseName
+}
+
+// head returns the head of a pull request.
+// If the head repository is a fork, the branch is namespaced with the owner.
+func (r *repoConfig) head() string {
+	if r.isFork() {
+		return r.headOwner + ":" + r.headBranch
+	}
+	return r.headBranch
+}
+
+// buildRepoConfig resolves repositories and branches.
+// In pull request mode, if the head branch isn't configured, defaultHeadBranch is used.
+// If defaultHeadBranch is also empty, the default branch of the head repository is used.
+func (c *Controller) buildRepoConfig(ctx context.Context, logger *slog.Logger, repo config.Repository, serverURL, defaultHeadBranch string) (*repoConfig, error) {
+	cfg := &repoConfig{
+		headOwner:   repo.Owner,
+		headName:    repo.Name,
+		pullRequest: repo.PullRequest.Enabled,
+		draft:       repo.PullRequest.Draft,
+	}
+	cfg.headURL = fmt.Sprintf("%s/%s/%s", serverURL, cfg.headOwner, cfg.headName)
+
+	if !cfg.pullRequest {
+		branch, err := c.getBranch(ctx, logger, repo)
+		if err != nil {
+			return nil, err
+		}
+		cfg.headBranch = branch
+		cfg.baseOwner = cfg.headOwner
+		cfg.baseName = cfg.headName
+		cfg.baseBranch = branch
+		cfg.baseURL = cfg.headURL
+		return cfg, nil
+	}
+
+	cfg.baseOwner = repo.PullRequest.Base.Owner
+	if cfg.baseOwner == "" {
+		cfg.baseOwner = cfg.headOwner
+	}
+	cfg.baseName = repo.PullRequest.Base.Name
+	if cfg.baseName == "" {
+		cfg.baseName = cfg.headName
+	}
+	cfg.baseURL = fmt.Sprintf("%s/%s/%s", serverURL, cfg.baseOwner, cfg.baseName)
+
+	cfg.baseBranch = repo.PullRequest.Base.Branch
+	if cfg.baseBranch == "" {
+		var err error
+		cfg.baseBranch, err = c.getDefaultBranch(ctx, logger, cfg.baseOwner, cfg.baseName)
+		if err != nil {
+			return nil, fmt.Errorf("get base repository default branch: %w", err)
+		}
+	}
+
+	cfg.headBranch = repo.Branch
+	if cfg.headBranch == "" {
+		cfg.headBranch = defaultHeadBranch
+	}
+	if cfg.headBranch == "" {
+		var err error
+		cfg.headBranch, err = c.getDefaultBranch(ctx, logger, cfg.headOwner, cfg.headName)
+		if err != nil {
+			return nil, fmt.Errorf("get head repository default branch: %w", err)
+		}
+	}
+	if !cfg.isFork() && cfg.headBranch == cfg.baseBranch {
+		return nil, fmt.Errorf("head branch must be different from the base branch %s to create a pull request", cfg.baseBranch)
+	}
+	return cfg, nil
+}
+
+// setupRepo prepares a local repository in workDir/dirName.
+// In pull request mode, the head branch is created from the base branch of the base repository.
+// The directory is removed beforehand because it may remain when a release is resumed.
+func (c *Controller) setupRepo(ctx context.Context, logger *slog.Logger, workDir, dirName string, cfg *repoConfig) (string, error) {
+	logger.Info("setting up repository",
+		"base", cfg.baseURL,
+		"head", cfg.headURL,
+		"branch", cfg.headBranch)
+
+	repoDir := filepath.Join(workDir, dirName)
+	if err := c.fs.RemoveAll(repoDir); err != nil {
+		return "", fmt.Errorf("remove a directory: %w", err)
+	}
+
+	if !cfg.pullRequest {
+		exists, err := c.remoteBranchExists(ctx, logger, cfg.headURL, cfg.headBranch)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			if err := c.exec.Run(ctx, logger, workDir, "git", "clone", "--depth", "1", "--branch", cfg.headBranch, cfg.headURL, dirName); err != nil {
+				return "", fmt.Errorf("git clone: %w", err)
+			}
+			return repoDir, nil
+		}
+		// The branch is created from the default branch and pushed.
+		// The repository may be empty, for example a new AUR package, then the first commit is created on the branch.
+		logger.Info("the branch doesn't exist on the remote, so it's created", "branch", cfg.headBranch)
+		if err := c.exec.Run(ctx, logger, workDir, "git", "clone", "--depth", "1", cfg.headURL, dirName); err != nil {
+			return "", fmt.Errorf("git clone: %w", err)
+		}
+		if err := c.exec.Run(ctx, logger, repoDir, "git", "checkout", "-b", cfg.headBranch); err != nil {
+			return "", fmt.Errorf("create the branch %s: %w", cfg.headBranch, err)
+		}
+		return repoDir, nil
+	}
+
+	if err := c.exec.Run(ctx, logger, workDir, "git", "init", dirName); err != nil {
+		return "", fmt.Errorf("git init: %w", err)
+	}
+
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "remote", "add", "origin", cfg.baseURL); err != nil {
+		return "", fmt.Errorf("add origin remote: %w", err)
+	}
+
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "fetch", "--depth=1", "origin", cfg.baseBranch); err != nil {
+		return "", fmt.Errorf("fetch origin: %w", err)
+	}
+
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "checkout", "-b", cfg.headBranch, "origin/"+cfg.baseBranch); err != nil {
+		return "", fmt.Errorf("checkout branch: %w", err)
+	}
+
+	return repoDir, nil
+}
+
+// remoteBranchExists returns true if the branch exists in the remote repository.
+func (c *Controller) remoteBranchExists(ctx context.Context, logger *slog.Logger, remoteURL, branch string) (bool, error) {
+	ref := "refs/heads/" + branch
+	out, err := c.exec.Output(ctx, logger, "", "git", "ls-remote", "--heads", remoteURL, ref)
+	if err != nil {
+		return false, fmt.Errorf("list branches of the remote repository: %w", err)
+	}
+	for line := range strings.Lines(out) {
+		if _, name, ok := strings.Cut(strings.TrimSpace(line), "\t"); ok && name == ref {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pushBranch pushes the head branch.
+// If the head repository is a fork of the base repository, it's pushed to the remote fork.
+func (c *Controller) pushBranch(ctx context.Context, logger *slog.Logger, repoDir string, cfg *repoConfig) error {
+	remote := "origin"
+	if cfg.isFork() {
+		remote = "fork"
+		if err := c.exec.Run(ctx, logger, repoDir, "git", "remote", "add", remote, cfg.headURL); err != nil {
+			return fmt.Errorf("add fork remote: %w", err)
+		}
+	}
+
+	if err := c.runOrPrint(ctx, logger, repoDir, "git", "push", remote, cfg.headBranch); err != nil {
+		return fmt.Errorf("git push: %w", err)
+	}
+
+	return nil
+}
+
+// repoTarget is a set of files pushed to a repository.
+type repoTarget struct {
+	// key is the key of the repository in the state file.
+	key string
+	// publisher is used in logs.
+	publisher string
+	repo      config.Repository
+	// dirName is the directory name of the local repository.
+	dirName string
+	// defaultHeadBranch is the head branch in pull request mode if the branch isn't configured.
+	defaultHeadBranch string
+	commitMessage     string
+	author            config.CommitAuthor
+	// prTitle is the title of the pull request. If it's empty, commitMessage is used.
+	prTitle string
+	// prBodyFile is the path of a file in the base repository used as the body of the pull request.
+	// If the file doesn't exist, the body is empty.
+	prBodyFile string
+	// external is true if the repository isn't hosted on GitHub, then git is always used to push commits.
+	external bool
+	// copyFiles copies files from artifacts into the local repository.
+	// It returns paths of copied files relative to the repository root.
+	copyFiles func(repoDir string) ([]string, error)
+}
+
+// pushRepo clones a repository, copies files, commits, and pushes them.
+// In pull request mode a pull request is created too.
+// If artifacts aren't available in dry-run mode, only the plan is printed.
+func (c *Controller) pushRepo(ctx context.Context, logger *slog.Logger, cfg *config.Config, t *repoTarget, artifactDir, workDir, serverURL string) error {
+	logger = logger.With("publisher", t.publisher)
+	if c.isPushed(logger, t) {
+		c.recordResult(t, nil, statusSkipped, nil)
+		return nil
+	}
+
+	rc, err := c.buildRepoConfig(ctx, logger, t.repo, serverURL, t.defaultHeadBranch)
+	if err != nil {
+		c.recordResult(t, nil, statusFailed, err)
+		return err
+	}
+
+	return c.pushRepoConfig(ctx, logger, cfg, t, rc, artifactDir, workDir)
+}
+
+// pushRepoConfig is same as pushRepo but the repository and branches are already resolved.
+// It's also called directly for repositories which aren't hosted on GitHub.
+// The result is recorded for the summary.
+func (c *Controller) pushRepoConfig(ctx context.Context, logger *slog.Logger, cfg *config.Config, t *repoTarget, rc *repoConfig, artifactDir, workDir string) error {
+	err := c.publishRepo(ctx, logger, cfg, t, rc, artifactDir, workDir)
+	status := statusPushed
+	switch {
+	case c.journal.repo(t.key).UpToDate:
+		status = statusUpToDate
+	case artifactDir == "" || c.param.DryRun:
+		status = statusPlanned
+	}
+	c.recordResult(t, rc, status, err)
+	return err
+}
+
+func (c *Controller) publishRepo(ctx context.Context, logger *slog.Logger, cfg *config.Config, t *repoTarget, rc *repoConfig, artifactDir, workDir string) error {
+	if artifactDir == "" {
+		c.printRepoPlan(rc.headURL, rc.headBranch, t.commitMessage)
+		if rc.pullRequest {
+			c.printf("[dry-run] create a pull request to %s (base branch: %s)\n", rc.baseURL, rc.baseBranch)
+		}
+		return nil
+	}
+
+	repoDir := filepath.Join(workDir, t.dirName)
+	if c.journal.repo(t.key).Pushed {
+		logger.Info("skip pushing the branch as it was already pushed", "repo", rc.headURL)
+	} else {
+		push := c.commitAndPushRepo
+		if useGitDataAPI(cfg) && !t.external {
+			push = c.commitViaAPI
+		}
+		sha, err := push(ctx, logger, t, rc, workDir)
+		if errors.Is(err, errUpToDate) {
+			// Nothing is pushed, so no pull request is created either.
+			logger.Info("already up to date", "repo", rc.headURL)
+			return c.journal.updateRepo(t.key, func(rs *repoState) {
+				rs.Pushed = true
+				rs.UpToDate = true
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.journal.updateRepo(t.key, func(rs *repoState) {
+			rs.Pushed = true
+			rs.Commit = sha
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !rc.pullRequest {
+		return nil
+	}
+
+	return c.openPullRequest(ctx, logger, cfg, t, rc, repoDir, workDir)
+}
+
+// openPullRequest creates a pull request and records it in the state file.
+func (c *Controller) openPullRequest(ctx context.Context, logger *slog.Logger, cfg *config.Config, t *repoTarget, rc *repoConfig, repoDir, workDir string) error {
+	title := t.prTitle
+	if title == "" {
+		title = t.commitMessage
+	}
+	body, err := c.readPRBody(ctx, logger, cfg, t, rc, repoDir, workDir)
+	if err != nil {
+		return err
+	}
+
+	if c.param.Web {
+		if err := c.createPullRequestWeb(ctx, logger, rc, title, body); err != nil {
+			return err
+		}
+		return c.journal.updateRepo(t.key, func(rs *repoState) {
+			rs.PullRequestCreated = true
+		})
+	}
+
+	pr, err := c.createPullRequest(ctx, logger, rc, title, body)
+	if err != nil {
+		return err
+	}
+	if err := c.journal.updateRepo(t.key, func(rs *repoState) {
+		rs.PullRequestCreated = true
+		rs.PullRequestURL = pr.GetHTMLURL()
+	}); err != nil {
+		return err
+	}
+	return c.enableAutoMerge(ctx, logger, cfg, pr)
+}
+
+// isPushed returns true if the repository was already pushed and the pull request was created.
+func (c *Controller) isPushed(logger *slog.Logger, t *repoTarget) bool {
+	rs := c.journal.repo(t.key)
+	if rs.Pushed && (!t.repo.PullRequest.Enabled || rs.PullRequestCreated || rs.UpToDate) {
+		logger.Info("skip the repository as it was already pushed", "repo", t.key)
+		return true
+	}
+	return false
+}
+
+// readPRBody reads the body of a pull request from the base repository.
+// With the git backend, the file is read from the local repository, which is set up again if it was removed after the push.
+// With the GitHub API backend, the file is read via GitHub API.
+func (c *Controller) readPRBody(ctx context.Context, logger *slog.Logger, cfg *config.Config, t *repoTarget, rc *repoConfig, repoDir, workDir string) (string, error) {
+	if t.prBodyFile == "" {
+		return "", nil
+	}
+	if useGitDataAPI(cfg) {
+		return c.readFileViaAPI(ctx, rc.baseOwner, rc.baseName, rc.baseBranch, t.prBodyFile)
+	}
+	if exists, err := afero.DirExists(c.fs, repoDir); err != nil {
+		return "", fmt.Errorf("check if the repository directory exists: %w", err)
+	} else if !exists {
+		if _, err := c.setupRepo(ctx, logger, workDir, t.dirName, rc); err != nil {
+			return "", err
+		}
+	}
+	b, err := afero.ReadFile(c.fs, filepath.Join(repoDir, t.prBodyFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read %s: %w", t.prBodyFile, err)
+	}
+	return string(b), nil
+}
+
+// commitAndPushRepo commits files with git and pushes them. It returns the SHA of the pushed commit.
+func (c *Controller) commitAndPushRepo(ctx context.Context, logger *slog.Logger, t *repoTarget, rc *repoConfig, workDir string) (string, error) {
+	repoDir, err := c.setupRepo(ctx, logger, workDir, t.dirName, rc)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Info("committing and pushing changes")
+	if err := c.commitFiles(ctx, logger, t, repoDir); err != nil {
+		return "", err
+	}
+
+	if err := c.pushWithRetry(ctx, logger, t, rc, repoDir); err != nil {
+		return "", err
+	}
+	if c.param.DryRun {
+		return "", nil
+	}
+	sha, err := c.exec.Output(ctx, logger, repoDir, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("get the pushed commit: %w", err)
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// commitFiles copies files into the local repository and commits them.
+// If nothing is changed, errUpToDate is returned.
+func (c *Controller) commitFiles(ctx context.Context, logger *slog.Logger, t *repoTarget, repoDir string) error {
+	files, err := t.copyFiles(repoDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("no file is found in the artifact")
+	}
+
+	if err := c.exec.Run(ctx, logger, repoDir, "git", append([]string{"add"}, files...)...); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+
+	// git commit fails if nothing is changed, for example when a release is rerun.
+	// The status is limited to copied files because other files may be removed in the working tree.
+	// e.g. winget removes the whole manifests directory before copying manifests.
+	status, err := c.exec.Output(ctx, logger, repoDir, "git", append([]string{"status", "--porcelain", "--"}, files...)...)
+	if err != nil {
+		return fmt.Errorf("check changes: %w", err)
+	}
+	if status == "" {
+		return errUpToDate
+	}
+
+	if err := c.printDiff(ctx, logger, repoDir); err != nil {
+		return err
+	}
+
+	commitArgs, err := gitCommitArgs(t.author, t.commitMessage)
+	if err != nil {
+		return err
+	}
+	if err := c.runOrPrint(ctx, logger, repoDir, "git", commitArgs...); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+	return nil
+}
+
+// createPullRequest creates a pull request via GitHub API.
+// In dry-run mode the pull request is printed instead.
+func (c *Controller) createPullRequest(ctx context.Context, logger *slog.Logger, rc *repoConfig, title, body string) (*github.PullRequest, error) {
+	head := rc.head()
+	if c.param.DryRun {
+		c.printf("[dry-run] create a pull request %q to %s (base branch: %s, head: %s, draft: %t)\n", title, rc.baseURL, rc.baseBranch, head, rc.draft)
+		return &github.PullRequest{}, nil
+	}
+
+	logger.Info("creating pull request", "base", rc.baseURL, "head", head)
+	pr, _, err := c.ghPR.Create(ctx, rc.baseOwner, rc.baseName, github.CreatePullRequest{
+		Title: github.Ptr(title),
+		Head:  head,
+		Base:  rc.baseBranch,
+		Body:  github.Ptr(body),
+		Draft: github.Ptr(rc.draft),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create a pull request: %w", err)
+	}
+	logger.Info("created pull request", "number", pr.GetNumber(), "url", pr.GetHTMLURL())
+	c.printf("created a pull request #%d: %s\n", pr.GetNumber(), pr.GetHTMLURL())
+	return pr, nil
+}
+
+// createPullRequestWeb opens the page to create a pull request in a web browser with GitHub CLI.
+// The pull request isn't created until it's submitted in the browser, so its URL isn't known.
+func (c *Controller) createPullRequestWeb(ctx context.Context, logger *slog.Logger, rc *repoConfig, title, body string) error {
+	head := rc.head()
+	logger.Info("opening the page to create a pull request", "base", rc.baseURL, "head", head)
+	if err := c.runOrPrint(ctx, logger, "", "gh", "pr", "create",
+		"--repo", rc.baseOwner+"/"+rc.baseName,
+		"--base", rc.baseBranch,
+		"--head", head,
+		"--title", title,
+		"--body", body,
+		"--web"); err != nil {
+		return fmt.Errorf("create a pull request: %w", err)
+	}
+	return nil
+}
+
+// enableAutoMerge enables auto-merge of a pull request with GitHub CLI.
+// GitHub supports auto-merge only in GraphQL API.
+func (c *Controller) enableAutoMerge(ctx context.Context, logger *slog.Logger, cfg *config.Config, pr *github.PullRequest) error {
+	if !c.param.AutoMerge && !cfg.Rgo.PullRequest.AutoMerge {
+		return nil
+	}
+	method := cfg.Rgo.PullRequest.MergeMethod
+	if method == "" {
+		method = "squash"
+	}
+	switch method {
+	case "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("merge method must be merge, squash, or rebase: %s", method)
+	}
+	if err := c.runOrPrint(ctx, logger, "", "gh", "pr", "merge", "--auto", "--"+method, pr.GetHTMLURL()); err != nil {
+		return fmt.Errorf("enable auto-merge: %w", err)
+	}
+	return nil
+}
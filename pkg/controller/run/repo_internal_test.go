@@ -0,0 +1,301 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+type mockPullRequestsClient struct {
+	createFunc func(ctx context.Context, owner, repo string, body github.CreatePullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+func (m *mockPullRequestsClient) Create(ctx context.Context, owner, repo string, body github.CreatePullRequest) (*github.PullRequest, *github.Response, error) {
+	return m.createFunc(ctx, owner, repo, body)
+}
+
+func TestController_pushRepo_pullRequest(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/dist/scoop/foo.json", []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var commands []string
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return nil
+		},
+		outputFunc: outputChanged,
+	}
+	var created github.CreatePullRequest
+	c := New(fs, &ParamRun{Version: "v1.0.0", AutoMerge: true}, exec, &GitHub{
+		PullRequests: &mockPullRequestsClient{
+			createFunc: func(_ context.Context, owner, repo string, body github.CreatePullRequest) (*github.PullRequest, *github.Response, error) {
+				if owner != "suzuki-shunsuke" || repo != "scoop-bucket" {
+					t.Errorf("pull request is created to %s/%s", owner, repo)
+				}
+				created = body
+				return &github.PullRequest{
+					Number:  github.Ptr(1),
+					HTMLURL: github.Ptr("https://github.com/suzuki-shunsuke/scoop-bucket/pull/1"),
+				}, nil, nil
+			},
+		},
+	})
+	cfg := &config.Config{ProjectName: "foo"}
+	scoop := config.Scoop{Repository: config.Repository{
+		Owner: "octocat",
+		Name:  "scoop-bucket",
+		PullRequest: config.PullRequest{
+			Enabled: true,
+			Draft:   true,
+			Base: config.PullRequestBase{
+				Owner:  "suzuki-shunsuke",
+				Branch: "main",
+			},
+		},
+	}}
+	key := repoKey("scoops", "octocat", "scoop-bucket")
+	if err := c.pushScoop(t.Context(), slog.Default(), cfg, scoop, "/dist/scoop", "/work", "https://github.com"); err != nil {
+		t.Fatalf("pushScoop() error = %v, want nil", err)
+	}
+
+	exp := []string{
+		"git init scoop-bucket",
+		"git remote add origin https://github.com/suzuki-shunsuke/scoop-bucket",
+		"git fetch --depth=1 origin main",
+		"git checkout -b foo-v1.0.0 origin/main",
+		"git add foo.json",
+		"git commit -m Scoop update for foo version v1.0.0",
+		"git remote add fork https://github.com/octocat/scoop-bucket",
+		"git push fork foo-v1.0.0",
+		"gh pr merge --auto --squash https://github.com/suzuki-shunsuke/scoop-bucket/pull/1",
+	}
+	if diff := cmp.Diff(exp, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+	expPR := github.CreatePullRequest{
+		Title: github.Ptr("Scoop update for foo version v1.0.0"),
+		Head:  "octocat:foo-v1.0.0",
+		Base:  "main",
+		Body:  github.Ptr(""),
+		Draft: github.Ptr(true),
+	}
+	if diff := cmp.Diff(expPR, created); diff != "" {
+		t.Errorf("pull request mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(repoState{
+		Pushed:             true,
+		PullRequestCreated: true,
+		PullRequestURL:     "https://github.com/suzuki-shunsuke/scoop-bucket/pull/1",
+	}, c.journal.repo(key)); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestController_pushRepo_upToDate(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/dist/scoop/foo.json", []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var commands []string
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return nil
+		},
+		outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, args ...string) (string, error) {
+			// Files which rgo doesn't commit are changed in the working tree.
+			if diff := cmp.Diff([]string{"status", "--porcelain", "--", "foo.json"}, args); diff != "" {
+				return " D bucket/bar.json\n?? baz.json\n", nil
+			}
+			return "", nil
+		},
+	}
+	c := New(fs, &ParamRun{Version: "v1.0.0"}, exec, &GitHub{
+		PullRequests: &mockPullRequestsClient{
+			createFunc: func(_ context.Context, _, _ string, _ github.CreatePullRequest) (*github.PullRequest, *github.Response, error) {
+				t.Error("a pull request is created though nothing is pushed")
+				return &github.PullRequest{}, nil, nil
+			},
+		},
+	})
+	cfg := &config.Config{ProjectName: "foo"}
+	scoop := config.Scoop{Repository: config.Repository{
+		Owner: "octocat",
+		Name:  "scoop-bucket",
+		PullRequest: config.PullRequest{
+			Enabled: true,
+			Base:    config.PullRequestBase{Branch: "main"},
+		},
+	}}
+	if err := c.pushScoop(t.Context(), slog.Default(), cfg, scoop, "/dist/scoop", "/work", "https://github.com"); err != nil {
+		t.Fatalf("pushScoop() error = %v, want nil", err)
+	}
+
+	exp := []string{
+		"git init scoop-bucket",
+		"git remote add origin https://github.com/octocat/scoop-bucket",
+		"git fetch --depth=1 origin main",
+		"git checkout -b foo-v1.0.0 origin/main",
+		"git add foo.json",
+	}
+	if diff := cmp.Diff(exp, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(repoState{Pushed: true, UpToDate: true}, c.journal.repo(repoKey("scoops", "octocat", "scoop-bucket"))); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+	if rows := c.results.list(); len(rows) != 1 || rows[0].status != statusUpToDate {
+		t.Errorf("status isn't up-to-date: %+v", rows)
+	}
+}
+
+func TestController_setupRepo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		// remote is the output of git ls-remote.
+		remote string
+		exp    []string
+	}{
+		{
+			name:   "branch exists",
+			remote: "abc\trefs/heads/release\n",
+			exp: []string{
+				"git clone --depth 1 --branch release https://github.com/octocat/tap tap",
+			},
+		},
+		{
+			name: "branch doesn't exist yet",
+			// A branch whose name ends with the branch isn't matched.
+			remote: "abc\trefs/heads/foo/release\n",
+			exp: []string{
+				"git clone --depth 1 https://github.com/octocat/tap tap",
+				"git checkout -b release",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var commands []string
+			exec := &mockExecutor{
+				runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+					commands = append(commands, name+" "+strings.Join(args, " "))
+					return nil
+				},
+				outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, args ...string) (string, error) {
+					if diff := cmp.Diff([]string{"ls-remote", "--heads", "https://github.com/octocat/tap", "refs/heads/release"}, args); diff != "" {
+						t.Errorf("args mismatch (-want +got):\n%s", diff)
+					}
+					return tt.remote, nil
+				},
+			}
+			c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
+			rc := &repoConfig{
+				headOwner:  "octocat",
+				headName:   "tap",
+				headBranch: "release",
+				headURL:    "https://github.com/octocat/tap",
+			}
+			repoDir, err := c.setupRepo(t.Context(), slog.Default(), "/work", "tap", rc)
+			if err != nil {
+				t.Fatalf("setupRepo() error = %v, want nil", err)
+			}
+			if repoDir != "/work/tap" {
+				t.Errorf("setupRepo() = %s, want /work/tap", repoDir)
+			}
+			if diff := cmp.Diff(tt.exp, commands); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestController_buildRepoConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		repo    config.Repository
+		exp     *repoConfig
+		wantErr bool
+	}{
+		{
+			name: "push directly",
+			repo: config.Repository{Owner: "octocat", Name: "tap", Branch: "main"},
+			exp: &repoConfig{
+				headOwner:  "octocat",
+				headName:   "tap",
+				headBranch: "main",
+				baseOwner:  "octocat",
+				baseName:   "tap",
+				baseBranch: "main",
+				headURL:    "https://github.com/octocat/tap",
+				baseURL:    "https://github.com/octocat/tap",
+			},
+		},
+		{
+			name: "pull request in the same repository",
+			repo: config.Repository{
+				Owner: "octocat",
+				Name:  "tap",
+				PullRequest: config.PullRequest{
+					Enabled: true,
+					Base:    config.PullRequestBase{Branch: "main"},
+				},
+			},
+			exp: &repoConfig{
+				headOwner:   "octocat",
+				headName:    "tap",
+				headBranch:  "foo-v1.0.0",
+				baseOwner:   "octocat",
+				baseName:    "tap",
+				baseBranch:  "main",
+				headURL:     "https://github.com/octocat/tap",
+				baseURL:     "https://github.com/octocat/tap",
+				pullRequest: true,
+			},
+		},
+		{
+			name: "head branch is same as the base branch",
+			repo: config.Repository{
+				Owner:  "octocat",
+				Name:   "tap",
+				Branch: "main",
+				PullRequest: config.PullRequest{
+					Enabled: true,
+					Base:    config.PullRequestBase{Branch: "main"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0"}, &mockExecutor{}, nil)
+			got, err := c.buildRepoConfig(t.Context(), slog.Default(), tt.repo, "https://github.com", "foo-v1.0.0")
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("buildRepoConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("buildRepoConfig() error = nil, want error")
+			}
+			if diff := cmp.Diff(tt.exp, got, cmp.AllowUnexported(repoConfig{})); diff != "" {
+				t.Errorf("buildRepoConfig() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
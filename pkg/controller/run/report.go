@@ -0,0 +1,180 @@
+package run
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Output formats of Run.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// report is a machine-readable record of a release.
+type report struct {
+	Version    string          `json:"version"`
+	TagPushed  bool            `json:"tag_pushed"`
+	RunID      string          `json:"run_id,omitempty"`
+	DryRun     bool            `json:"dry_run"`
+	Status     string          `json:"status"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Phases     []*phaseReport  `json:"phases"`
+	Artifacts  []*artifactFile `json:"artifacts,omitempty"`
+	Results    []*resultReport `json:"results,omitempty"`
+}
+
+// phaseReport is the timing of a phase of a release.
+type phaseReport struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Duration is in seconds.
+	Duration float64 `json:"duration"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// artifactFile is a file in downloaded artifacts.
+type artifactFile struct {
+	// Path is relative to the directory where artifacts are extracted.
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// resultReport is the result of a repository.
+type resultReport struct {
+	Publisher      string `json:"publisher"`
+	Repository     string `json:"repository,omitempty"`
+	Branch         string `json:"branch,omitempty"`
+	Commit         string `json:"commit,omitempty"`
+	PullRequestURL string `json:"pull_request_url,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// phase runs a phase of a release and records its timing in the report.
+func (c *Controller) phase(name string, f func() error) error {
+	startedAt := time.Now()
+	err := f()
+	if c.report == nil {
+		return err
+	}
+	finishedAt := time.Now()
+	p := &phaseReport{
+		Name:       name,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Duration:   finishedAt.Sub(startedAt).Seconds(),
+	}
+	if err != nil {
+		p.Error = err.Error()
+	}
+	c.report.Phases = append(c.report.Phases, p)
+	return err
+}
+
+// listArtifactFiles returns files in the directory with their checksums.
+// Directories where repositories are cloned are excluded.
+func (c *Controller) listArtifactFiles(root string) ([]*artifactFile, error) {
+	var files []*artifactFile
+	if err := afero.Walk(c.fs, root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p == filepath.Join(root, "publishers") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		checksum, err := c.sha256File(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return fmt.Errorf("get a relative path: %w", err)
+		}
+		files = append(files, &artifactFile{
+			Path:   filepath.ToSlash(rel),
+			Size:   info.Size(),
+			SHA256: checksum,
+		})
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("list files in artifacts: %w", err)
+	}
+	return files, nil
+}
+
+func (c *Controller) sha256File(p string) (string, error) {
+	f, err := c.fs.Open(p)
+	if err != nil {
+		return "", fmt.Errorf("open a file: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("read a file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeReport completes the report and writes it to the report file and stdout.
+// runErr is the error of the release.
+func (c *Controller) writeReport(stdout io.Writer, runErr error) error {
+	rep := c.report
+	rep.FinishedAt = time.Now()
+	st := c.journal.get()
+	rep.TagPushed = st.TagPushed
+	if st.RunID != "" {
+		rep.RunID = st.RunID
+	}
+	rep.Status = "succeeded"
+	if runErr != nil {
+		rep.Status = "failed"
+		rep.Error = runErr.Error()
+	}
+	for _, row := range c.results.list() {
+		r := &resultReport{
+			Publisher:      row.publisher,
+			Repository:     row.repository,
+			Branch:         row.branch,
+			Commit:         row.commit,
+			PullRequestURL: row.pullRequestURL,
+			Status:         row.status,
+		}
+		if row.err != nil {
+			r.Error = row.err.Error()
+		}
+		rep.Results = append(rep.Results, r)
+	}
+
+	b, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode a report as JSON: %w", err)
+	}
+	b = append(b, '\n')
+	if c.param.ReportFile != "" {
+		if err := afero.WriteFile(c.fs, c.param.ReportFile, b, filePermission); err != nil {
+			return fmt.Errorf("write a report file: %w", err)
+		}
+	}
+	if c.param.Output == outputJSON && stdout != nil {
+		if _, err := stdout.Write(b); err != nil {
+			return fmt.Errorf("output a report: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_listArtifactFiles(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	for name, content := range map[string]string{
+		"/tmp/rgo/goreleaser/homebrew/foo.rb":                    "class Foo\n",
+		"/tmp/rgo/goreleaser/scoop/foo.json":                     "{}",
+		"/tmp/rgo/publishers/scoop/scoop-bucket/bucket/foo.json": "{}",
+	} {
+		if err := afero.WriteFile(fs, name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := New(fs, &ParamRun{}, nil, nil)
+	files, err := c.listArtifactFiles("/tmp/rgo")
+	if err != nil {
+		t.Fatalf("listArtifactFiles() error = %v, want nil", err)
+	}
+	exp := []*artifactFile{
+		{Path: "goreleaser/homebrew/foo.rb", Size: 10, SHA256: "6b2f2baa237102a4f6565694f94e9ef08c517c861cf7877df8bd4d864e061d65"},
+		{Path: "goreleaser/scoop/foo.json", Size: 2, SHA256: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"},
+	}
+	if diff := cmp.Diff(exp, files); diff != "" {
+		t.Errorf("listArtifactFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestController_writeReport(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	stdout := &bytes.Buffer{}
+	c := New(fs, &ParamRun{Version: "v1.0.0", ReportFile: "/report.json", Output: outputJSON}, &mockExecutor{}, nil)
+	c.report = &report{Version: "v1.0.0"}
+	if err := c.journal.update(func(st *state) {
+		st.TagPushed = true
+		st.RunID = "123"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.phase("tag", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.phase("publish", func() error { return errors.New("push failed") }); err == nil {
+		t.Fatal("phase() error = nil, want error")
+	}
+	c.recordResult(&repoTarget{key: "scoop", publisher: "scoop"}, &repoConfig{headOwner: "octocat", headName: "scoop-bucket", headBranch: "main"}, statusPushed, errors.New("push failed"))
+	// The AUR repository was already pushed before the release is resumed.
+	if err := c.journal.updateRepo("aurs/foo-bin", func(rs *repoState) {
+		rs.Pushed = true
+		rs.Commit = "def456"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	aur := config.AUR{Name: "foo-bin", GitURL: "ssh://aur@aur.archlinux.org/foo-bin.git"}
+	if err := c.pushAUR(t.Context(), slog.Default(), &config.Config{ProjectName: "foo"}, aur, "/dist/aur", "/work"); err != nil {
+		t.Fatalf("pushAUR() error = %v, want nil", err)
+	}
+
+	if err := c.writeReport(stdout, errors.New("process Scoop: push failed")); err != nil {
+		t.Fatalf("writeReport() error = %v, want nil", err)
+	}
+	b, err := afero.ReadFile(fs, "/report.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(b), stdout.String()); diff != "" {
+		t.Errorf("the report file and stdout mismatch (-file +stdout):\n%s", diff)
+	}
+	rep := &report{}
+	if err := json.Unmarshal(b, rep); err != nil {
+		t.Fatal(err)
+	}
+	exp := &report{
+		Version:   "v1.0.0",
+		TagPushed: true,
+		RunID:     "123",
+		Status:    "failed",
+		Error:     "process Scoop: push failed",
+		Phases: []*phaseReport{
+			{Name: "tag"},
+			{Name: "publish", Error: "push failed"},
+		},
+		Results: []*resultReport{
+			{Publisher: "scoop", Repository: "octocat/scoop-bucket", Branch: "main", Status: statusFailed, Error: "push failed"},
+			{Publisher: "aur", Repository: "ssh://aur@aur.archlinux.org/foo-bin.git", Branch: "master", Commit: "def456", Status: statusSkipped},
+		},
+	}
+	opts := cmp.Options{
+		cmpopts.IgnoreFields(report{}, "StartedAt", "FinishedAt"),
+		cmpopts.IgnoreFields(phaseReport{}, "StartedAt", "FinishedAt", "Duration"),
+	}
+	if diff := cmp.Diff(exp, rep, opts); diff != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestController_Run_invalidOutput(t *testing.T) {
+	t.Parallel()
+	c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0", Output: "yaml"}, nil, nil)
+	if err := c.Run(t.Context(), slog.Default()); err == nil {
+		t.Fatal("Run() error = nil, want error")
+	}
+}
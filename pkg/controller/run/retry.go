@@ -0,0 +1,88 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// maxPushAttempts is the maximum number of attempts to push a branch.
+	maxPushAttempts = 4
+	// defaultPushRetryInterval is the base interval between attempts to push a branch.
+	// It's doubled on each attempt and a random jitter up to the base interval is added.
+	defaultPushRetryInterval = 2 * time.Second
+)
+
+// pushWithRetry pushes the head branch.
+// A repository such as scoop-bucket may be shared by projects released at the same time,
+// so if the push is rejected because the remote branch was updated, the commit is rebased onto the remote branch and pushed again.
+// Other errors such as authentication failures are returned immediately.
+// In pull request mode the head branch is used only by rgo, so the push isn't retried.
+func (c *Controller) pushWithRetry(ctx context.Context, logger *slog.Logger, t *repoTarget, rc *repoConfig, repoDir string) error {
+	for attempt := 1; ; attempt++ {
+		err := c.pushBranch(ctx, logger, repoDir, rc)
+		if err == nil {
+			return nil
+		}
+		if rc.pullRequest || c.param.DryRun {
+			return err
+		}
+		moved, ferr := c.remoteBranchMoved(ctx, logger, rc, repoDir)
+		if ferr != nil {
+			return errors.Join(err, ferr)
+		}
+		if !moved || attempt >= maxPushAttempts {
+			return err
+		}
+		d := c.pushRetryInterval<<(attempt-1) + rand.N(c.pushRetryInterval) //nolint:gosec
+		logger.Warn("the push is rejected as the remote branch was updated. Rebase the commit onto the remote branch and retry",
+			"attempt", attempt,
+			"wait", d,
+			"error", err)
+		if err := wait(ctx, d); err != nil {
+			return err
+		}
+		if err := c.rebaseCommit(ctx, logger, t, repoDir); err != nil {
+			return err
+		}
+	}
+}
+
+// remoteBranchMoved fetches the remote head branch into FETCH_HEAD and returns true if HEAD doesn't include it.
+// Then the push was rejected as non-fast-forward.
+func (c *Controller) remoteBranchMoved(ctx context.Context, logger *slog.Logger, rc *repoConfig, repoDir string) (bool, error) {
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "fetch", "origin", rc.headBranch); err != nil {
+		return false, fmt.Errorf("fetch the branch %s: %w", rc.headBranch, err)
+	}
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "merge-base", "--is-ancestor", "FETCH_HEAD", "HEAD"); err != nil {
+		return true, nil //nolint:nilerr
+	}
+	return false, nil
+}
+
+// rebaseCommit rebases the commit onto the fetched remote head branch.
+// The commit changes only files copied by rgo, so conflicts are always in them.
+// Then the commit is regenerated from artifacts on the remote branch instead of resolving conflicts.
+// If the remote branch already has the same files, errUpToDate is returned.
+func (c *Controller) rebaseCommit(ctx context.Context, logger *slog.Logger, t *repoTarget, repoDir string) error {
+	configArgs, err := gitConfigArgs(t.author)
+	if err != nil {
+		return err
+	}
+	if err := c.exec.Run(ctx, logger, repoDir, "git", append(configArgs, "rebase", "FETCH_HEAD")...); err == nil {
+		return nil
+	}
+
+	logger.Info("regenerating the commit as it conflicts with the remote branch")
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "rebase", "--abort"); err != nil {
+		return fmt.Errorf("abort the rebase: %w", err)
+	}
+	if err := c.exec.Run(ctx, logger, repoDir, "git", "reset", "--hard", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("reset the branch to the remote branch: %w", err)
+	}
+	return c.commitFiles(ctx, logger, t, repoDir)
+}
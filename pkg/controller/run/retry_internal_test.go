@@ -0,0 +1,186 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_pushWithRetry(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	tests := []struct {
+		name string
+		// rejected is the number of rejected pushes.
+		rejected int
+		// failed is true if pushes fail for other reasons than updates of the remote branch.
+		failed   bool
+		conflict bool
+		// upToDate is true if the remote branch already has the same files after the rebase.
+		upToDate bool
+		exp      []string
+		isErr    bool
+		// pushes is the expected number of pushes when an error is returned.
+		pushes int
+		state  repoState
+	}{
+		{
+			name:     "rebase",
+			rejected: 1,
+			exp: []string{
+				"git clone --depth 1 --branch main https://github.com/octocat/scoop-bucket scoop-bucket",
+				"git add foo.json",
+				"git commit -m Scoop update for foo version v1.0.0",
+				"git push origin main",
+				"git fetch origin main",
+				"git merge-base --is-ancestor FETCH_HEAD HEAD",
+				"git rebase FETCH_HEAD",
+				"git push origin main",
+			},
+			state: repoState{Pushed: true, Commit: "abc"},
+		},
+		{
+			name:     "regenerate the commit on conflicts",
+			rejected: 1,
+			conflict: true,
+			exp: []string{
+				"git clone --depth 1 --branch main https://github.com/octocat/scoop-bucket scoop-bucket",
+				"git add foo.json",
+				"git commit -m Scoop update for foo version v1.0.0",
+				"git push origin main",
+				"git fetch origin main",
+				"git merge-base --is-ancestor FETCH_HEAD HEAD",
+				"git rebase FETCH_HEAD",
+				"git rebase --abort",
+				"git reset --hard FETCH_HEAD",
+				"git add foo.json",
+				"git commit -m Scoop update for foo version v1.0.0",
+				"git push origin main",
+			},
+			state: repoState{Pushed: true, Commit: "abc"},
+		},
+		{
+			name:     "already pushed by another release",
+			rejected: 1,
+			conflict: true,
+			upToDate: true,
+			exp: []string{
+				"git clone --depth 1 --branch main https://github.com/octocat/scoop-bucket scoop-bucket",
+				"git add foo.json",
+				"git commit -m Scoop update for foo version v1.0.0",
+				"git push origin main",
+				"git fetch origin main",
+				"git merge-base --is-ancestor FETCH_HEAD HEAD",
+				"git rebase FETCH_HEAD",
+				"git rebase --abort",
+				"git reset --hard FETCH_HEAD",
+				"git add foo.json",
+			},
+			state: repoState{Pushed: true, UpToDate: true},
+		},
+		{
+			name:     "attempts are bounded",
+			rejected: maxPushAttempts,
+			isErr:    true,
+			pushes:   maxPushAttempts,
+		},
+		{
+			name:     "other errors aren't retried",
+			rejected: maxPushAttempts,
+			failed:   true,
+			isErr:    true,
+			pushes:   1,
+			exp: []string{
+				"git clone --depth 1 --branch main https://github.com/octocat/scoop-bucket scoop-bucket",
+				"git add foo.json",
+				"git commit -m Scoop update for foo version v1.0.0",
+				"git push origin main",
+				"git fetch origin main",
+				"git merge-base --is-ancestor FETCH_HEAD HEAD",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "/dist/scoop/foo.json", []byte("{}"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var commands []string
+			pushes := 0
+			statuses := 0
+			exec := &mockExecutor{
+				runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+					commands = append(commands, name+" "+strings.Join(args, " "))
+					switch args[0] {
+					case "push":
+						pushes++
+						if pushes <= tt.rejected {
+							return errors.New("rejected: non-fast-forward")
+						}
+					case "merge-base":
+						if !tt.failed {
+							// The remote branch was updated.
+							return errors.New("exit status 1")
+						}
+					case "rebase":
+						if tt.conflict && args[1] == "FETCH_HEAD" {
+							return errors.New("conflict")
+						}
+					}
+					return nil
+				},
+				outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, args ...string) (string, error) {
+					switch args[0] {
+					case "status":
+						statuses++
+						if tt.upToDate && statuses > 1 {
+							return "", nil
+						}
+						return " M foo.json\n", nil
+					case "rev-parse":
+						return "abc\n", nil
+					case "ls-remote":
+						return "abc\trefs/heads/main\n", nil
+					}
+					return "", nil
+				},
+			}
+			c := New(fs, &ParamRun{Version: "v1.0.0"}, exec, nil)
+			c.pushRetryInterval = time.Millisecond
+			cfg := &config.Config{ProjectName: "foo"}
+			scoop := config.Scoop{Repository: config.Repository{Owner: "octocat", Name: "scoop-bucket", Branch: "main"}}
+			err := c.pushScoop(t.Context(), slog.Default(), cfg, scoop, "/dist/scoop", "/work", "https://github.com")
+			if tt.isErr {
+				if err == nil {
+					t.Fatal("pushScoop() error = nil, want error")
+				}
+				if pushes != tt.pushes {
+					t.Errorf("the branch is pushed %d times, want %d", pushes, tt.pushes)
+				}
+				if tt.exp != nil {
+					if diff := cmp.Diff(tt.exp, commands); diff != "" {
+						t.Errorf("commands mismatch (-want +got):\n%s", diff)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pushScoop() error = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.exp, commands); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.state, c.journal.repo(repoKey("scoops", "octocat", "scoop-bucket"))); diff != "" {
+				t.Errorf("state mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,12 +2,12 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"slices"
-	"strings"
 	"time"
 
 	"github.com/spf13/afero"
@@ -16,58 +16,199 @@ import (
 
 type ParamRun struct {
 	ConfigFilePath string
-	Stderr         io.Writer
-	Version        string
-	RunID          string
-	Workflow       string
-	Publish        []string
+	// RgoConfigFilePath is the path of rgo's configuration file (.rgo.yaml).
+	RgoConfigFilePath string
+	// ArtifactName overrides the default artifact name.
+	ArtifactName string
+	// ArtifactPaths override artifacts and paths of files for each publisher.
+	// The format is <publisher>=[<artifact name>:]<path>.
+	ArtifactPaths []string
+	Stdout        io.Writer
+	Stderr        io.Writer
+	Version       string
+	RunID         string
+	Workflow      string
+	Publish       []string
+	// DryRun prints commands changing repositories instead of executing them.
+	DryRun bool
+	// Resume continues a failed release from the first unfinished phase.
+	Resume bool
+	// StateDir is a directory where state files are stored.
+	// If it's empty, .git/rgo is used.
+	StateDir string
+	// Repository is the released repository (<owner>/<name>).
+	// If it's empty, it's parsed from the URL of the remote origin.
+	Repository string
+	// RunDiscoveryTimeout is how long to look for the workflow run triggered by the tag.
+	RunDiscoveryTimeout time.Duration
+	// DistDir is a local directory laid out like GoReleaser's dist directory.
+	// It's used by Publish instead of GitHub Actions Artifacts.
+	DistDir string
+	// AutoMerge enables auto-merge of created pull requests.
+	AutoMerge bool
+	// Web opens pages to create pull requests in a web browser instead of creating them via GitHub API.
+	Web bool
+	// Prerelease overrides prerelease policies. The format is [<publisher>=]<policy>.
+	Prerelease []string
+	// Parallel is the maximum number of publishers processed concurrently.
+	Parallel int
+	// Force skips checks before creating a tag (dirty, unpushed, version, version-order, existing-tag, or all).
+	Force []string
+	// KeepGoing attempts all publishers and repositories even if some of them fail.
+	KeepGoing bool
+	// ReportFile is the path of a JSON report of the release.
+	ReportFile string
+	// Output is the output format (text or json).
+	// If it's json, the report is output to Stdout and other outputs are written to Stderr.
+	Output string
 }
 
+// Run creates a tag, waits for the release workflow, and publishes packages in artifacts.
+// If a report is requested, it's written even if the release fails.
 func (c *Controller) Run(ctx context.Context, logger *slog.Logger) error {
-	cfg, err := config.Read(c.fs, c.param.ConfigFilePath)
+	switch c.param.Output {
+	case "", outputText, outputJSON:
+	default:
+		return fmt.Errorf("output must be text or json: %s", c.param.Output)
+	}
+	stdout := c.param.Stdout
+	if c.param.Output == outputJSON {
+		param := *c.param
+		param.Stdout = param.Stderr
+		c.param = &param
+	}
+	c.report = &report{
+		Version:   c.param.Version,
+		DryRun:    c.param.DryRun,
+		StartedAt: time.Now(),
+	}
+
+	err := c.run(ctx, logger)
+	if c.param.ReportFile == "" && c.param.Output != outputJSON {
+		return err
+	}
+	if rerr := c.writeReport(stdout, err); rerr != nil {
+		return errors.Join(err, rerr)
+	}
+	return err
+}
+
+func (c *Controller) run(ctx context.Context, logger *slog.Logger) error {
+	cfg, err := c.readConfig()
 	if err != nil {
-		return fmt.Errorf("read a config file: %w", err)
+		return err
 	}
+	prerelease := isPrerelease(c.param.Version)
+	publishPrerelease := prerelease && c.applyPrereleasePolicy(logger, cfg)
 
-	runID, err := c.prepareRelease(ctx, logger)
+	j, err := c.openJournal(ctx, logger)
 	if err != nil {
 		return err
 	}
+	c.journal = j
+
+	// The local checkout is checked before creating a tag, and the checks can be skipped by --force.
+	if err := c.phase("preflight", func() error {
+		return c.doctor(ctx, logger, cfg, false)
+	}); err != nil {
+		return fmt.Errorf("preflight checks: %w", err)
+	}
 
-	// Skip for prerelease versions
-	if strings.Contains(c.param.Version, "-") {
+	var runID string
+	if err := c.phase("tag", func() error {
+		runID, err = c.prepareRelease(ctx, logger)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if prerelease && !publishPrerelease {
 		logger.Info("prerelease version detected, skipping package manager updates")
 		return nil
 	}
 
-	runID, err = c.waitForWorkflow(ctx, logger, runID)
-	if err != nil {
+	if err := c.phase("workflow", func() error {
+		runID, err = c.waitForWorkflow(ctx, logger, runID)
+		return err
+	}); err != nil {
 		return err
 	}
+	c.report.RunID = runID
 
-	tempDir, err := c.downloadReleaseArtifacts(ctx, logger, runID)
-	if err != nil {
+	var tempDir string
+	if err := c.phase("download", func() error {
+		tempDir, err = c.downloadReleaseArtifacts(ctx, logger, runID, c.artifactNames(cfg))
+		if err != nil || tempDir == "" {
+			return err
+		}
+		c.report.Artifacts, err = c.listArtifactFiles(tempDir)
+		return err
+	}); err != nil {
 		return err
 	}
 
-	if err := c.publishPackages(ctx, logger, cfg, tempDir); err != nil {
+	layout := &artifactLayout{
+		root:      tempDir,
+		artifacts: &cfg.Rgo.Artifacts,
+	}
+	if err := c.phase("publish", func() error {
+		return c.publishPackages(ctx, logger, cfg, layout, tempDir)
+	}); err != nil {
 		return err
 	}
 
+	if c.param.DryRun {
+		logger.Info("dry run completed")
+		return nil
+	}
+	if err := c.journal.update(func(st *state) {
+		st.Completed = true
+	}); err != nil {
+		return err
+	}
 	logger.Info("release completed successfully")
 	return nil
 }
 
 func (c *Controller) prepareRelease(ctx context.Context, logger *slog.Logger) (string, error) {
-	runID := c.param.RunID
-	if runID != "" {
-		return runID, nil
+	st := c.journal.get()
+	if st.RunID != "" {
+		return st.RunID, nil
+	}
+
+	var exists *existingTag
+	if st.TagCreated {
+		logger.Info("skip creating a tag as it was already created")
+	} else {
+		var err error
+		exists, err = c.checkBeforeTag(ctx, logger)
+		if err != nil {
+			return "", fmt.Errorf("check before creating a tag: %w", err)
+		}
+		if exists.local || exists.remote {
+			logger.Warn("skip creating a tag as it already exists", "tag", c.param.Version)
+		} else if err := c.createTag(ctx, logger, c.param.Version); err != nil {
+			return "", err
+		}
+		if err := c.journal.update(func(st *state) {
+			st.TagCreated = true
+		}); err != nil {
+			return "", err
+		}
 	}
 
-	if err := c.createTag(ctx, logger, c.param.Version); err != nil {
+	if st.TagPushed {
+		logger.Info("skip pushing a tag as it was already pushed")
+		return "", nil
+	}
+	if exists != nil && exists.remote {
+		logger.Warn("skip pushing a tag as it already exists on the remote", "tag", c.param.Version)
+	} else if err := c.pushTag(ctx, logger, c.param.Version); err != nil {
 		return "", err
 	}
-	if err := c.pushTag(ctx, logger, c.param.Version); err != nil {
+	if err := c.journal.update(func(st *state) {
+		st.TagPushed = true
+	}); err != nil {
 		return "", err
 	}
 	return "", nil
@@ -79,16 +220,23 @@ func (c *Controller) waitForWorkflow(ctx context.Context, logger *slog.Logger, r
 		workflow = "release.yaml"
 	}
 
+	if runID == "" && c.param.DryRun {
+		c.printf("[dry-run] wait for the workflow %s triggered by the tag %s\n", workflow, c.param.Version)
+		return "", nil
+	}
+
 	if runID == "" {
 		logger.Info("waiting for workflow to start")
-		if err := wait(ctx, 10*time.Second); err != nil { //nolint:mnd
-			return "", err
-		}
 		var err error
 		runID, err = c.getRunID(ctx, logger, workflow)
 		if err != nil {
 			return "", err
 		}
+		if err := c.journal.update(func(st *state) {
+			st.RunID = runID
+		}); err != nil {
+			return "", err
+		}
 	}
 
 	logger.Info("waiting for workflow to complete", "run_id", runID)
@@ -98,7 +246,23 @@ func (c *Controller) waitForWorkflow(ctx context.Context, logger *slog.Logger, r
 	return runID, nil
 }
 
-func (c *Controller) downloadReleaseArtifacts(ctx context.Context, logger *slog.Logger, runID string) (string, error) {
+func (c *Controller) downloadReleaseArtifacts(ctx context.Context, logger *slog.Logger, runID string, artifactNames []string) (string, error) {
+	if runID == "" {
+		// In dry-run mode the workflow run doesn't exist yet.
+		// Publishers are planned without artifacts.
+		c.printf("[dry-run] artifacts aren't available until the workflow run completes. Specify --run-id to plan changes of files\n")
+		return "", nil
+	}
+
+	if st := c.journal.get(); st.ArtifactsDownloaded && st.TempDir != "" {
+		if exists, err := afero.DirExists(c.fs, st.TempDir); err != nil {
+			return "", fmt.Errorf("check if the temporary directory exists: %w", err)
+		} else if exists {
+			logger.Info("reuse downloaded artifacts", "path", st.TempDir)
+			return st.TempDir, nil
+		}
+	}
+
 	tempDir, err := afero.TempDir(c.fs, "", "rgo-")
 	if err != nil {
 		return "", fmt.Errorf("create a temporary directory: %w", err)
@@ -106,37 +270,83 @@ func (c *Controller) downloadReleaseArtifacts(ctx context.Context, logger *slog.
 	logger.Info("created temporary directory", "path", tempDir)
 
 	logger.Info("downloading artifacts")
-	if err := c.downloadArtifacts(ctx, logger, tempDir, runID); err != nil {
+	if err := c.downloadArtifacts(ctx, logger, tempDir, runID, artifactNames); err != nil {
+		return "", err
+	}
+	if err := c.journal.update(func(st *state) {
+		st.TempDir = tempDir
+		st.ArtifactsDownloaded = true
+	}); err != nil {
 		return "", err
 	}
 	return tempDir, nil
 }
 
-func (c *Controller) publishPackages(ctx context.Context, logger *slog.Logger, cfg *config.Config, tempDir string) error {
+// publishPackages pushes files in artifacts to repositories.
+// Repositories are cloned into a directory of each publisher in workDir.
+// In dry-run mode artifacts may not be available, then changes of files aren't planned.
+func (c *Controller) publishPackages(ctx context.Context, logger *slog.Logger, cfg *config.Config, layout *artifactLayout, workDir string) error {
 	serverURL := os.Getenv("GITHUB_SERVER_URL")
 	if serverURL == "" {
 		serverURL = "https://github.com"
 	}
-	artifactName := "goreleaser"
-
-	if c.shouldPublish("homebrew") {
-		if err := c.processHomebrew(ctx, logger, cfg, tempDir, artifactName, serverURL); err != nil {
-			return fmt.Errorf("process Homebrew: %w", err)
-		}
-	}
 
-	if c.shouldPublish("scoop") {
-		if err := c.processScoop(ctx, logger, cfg, tempDir, artifactName, serverURL); err != nil {
-			return fmt.Errorf("process Scoop: %w", err)
-		}
+	tasks := []*publishTask{
+		{
+			publisher: "homebrew",
+			name:      "Homebrew",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processHomebrew(ctx, logger, cfg, layout.dir("homebrew"), workDir, serverURL)
+			},
+		},
+		{
+			publisher: "scoop",
+			name:      "Scoop",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processScoop(ctx, logger, cfg, layout.dir("scoop"), workDir, serverURL)
+			},
+		},
+		{
+			publisher: "winget",
+			name:      "Winget",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processWinget(ctx, logger, cfg, layout.dir("winget"), workDir, serverURL)
+			},
+		},
+		{
+			publisher: "aur",
+			name:      "AUR",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processAUR(ctx, logger, cfg, layout.dir("aur"), workDir)
+			},
+		},
+		{
+			publisher: "nix",
+			name:      "Nix",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processNix(ctx, logger, cfg, layout.dir("nix"), workDir, serverURL)
+			},
+		},
+		{
+			publisher: "krew",
+			name:      "Krew",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processKrew(ctx, logger, cfg, layout.dir("krew"), workDir, serverURL)
+			},
+		},
+		{
+			publisher: "git_files",
+			name:      "git files",
+			process: func(ctx context.Context, c *Controller, logger *slog.Logger, workDir string) error {
+				return c.processGitFiles(ctx, logger, cfg, layout, workDir, serverURL)
+			},
+		},
 	}
-
-	if c.shouldPublish("winget") {
-		if err := c.processWinget(ctx, logger, cfg, tempDir, artifactName); err != nil {
-			return fmt.Errorf("process Winget: %w", err)
-		}
-	}
-	return nil
+	err := c.runPublishTasks(ctx, logger, slices.DeleteFunc(tasks, func(task *publishTask) bool {
+		return !c.shouldPublish(task.publisher)
+	}), workDir)
+	c.printSummary()
+	return err
 }
 
 func wait(ctx context.Context, d time.Duration) error {
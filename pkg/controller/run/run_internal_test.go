@@ -1,9 +1,13 @@
 package run
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -16,7 +20,6 @@ const (
 	pubHomebrew = "homebrew"
 	pubScoop    = "scoop"
 	pubWinget   = "winget"
-	cmdRun      = "run"
 )
 
 // Mock Executor
@@ -39,6 +42,20 @@ func (m *mockExecutor) Output(ctx context.Context, logger *slog.Logger, dir stri
 	return "", nil
 }
 
+// outputChanged is an outputFunc of mockExecutor reporting that files are changed and remote branches exist.
+func outputChanged(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) (string, error) {
+	if name != "git" || len(args) == 0 {
+		return "", nil
+	}
+	switch args[0] {
+	case "status":
+		return " M changed\n", nil
+	case "ls-remote":
+		return "abc\t" + args[len(args)-1] + "\n", nil
+	}
+	return "", nil
+}
+
 // Mock RepositoriesClient
 type mockRepositoriesClient struct {
 	getFunc func(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
@@ -51,6 +68,10 @@ func (m *mockRepositoriesClient) Get(ctx context.Context, owner, repo string) (*
 	return &github.Repository{}, nil, nil
 }
 
+func (m *mockRepositoriesClient) GetContents(_ context.Context, _, _, _ string, _ *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	return nil, nil, nil, errors.New("not implemented")
+}
+
 func TestController_shouldPublish(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -199,6 +220,32 @@ func TestController_createTag(t *testing.T) {
 	})
 }
 
+func TestController_createTag_dryRun(t *testing.T) {
+	t.Parallel()
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			t.Errorf("unexpected command: %s %v", name, args)
+			return nil
+		},
+	}
+	stdout := &bytes.Buffer{}
+	c := New(afero.NewMemMapFs(), &ParamRun{DryRun: true, Stdout: stdout}, exec, nil)
+
+	if err := c.createTag(t.Context(), slog.Default(), "v1.0.0"); err != nil {
+		t.Errorf("createTag() error = %v, want nil", err)
+	}
+	if err := c.pushTag(t.Context(), slog.Default(), "v1.0.0"); err != nil {
+		t.Errorf("pushTag() error = %v, want nil", err)
+	}
+
+	exp := `[dry-run] git tag -m "chore: release v1.0.0" v1.0.0
+[dry-run] git push origin v1.0.0
+`
+	if diff := cmp.Diff(exp, stdout.String()); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestController_pushTag(t *testing.T) {
 	t.Parallel()
 
@@ -256,7 +303,7 @@ func TestController_getDefaultBranch(t *testing.T) {
 				}, nil, nil
 			},
 		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, nil, ghRepo)
+		c := New(afero.NewMemMapFs(), &ParamRun{}, nil, &GitHub{Repositories: ghRepo})
 
 		branch, err := c.getDefaultBranch(t.Context(), slog.Default(), "test-owner", "test-repo")
 		if err != nil {
@@ -274,7 +321,7 @@ func TestController_getDefaultBranch(t *testing.T) {
 				return nil, nil, errors.New("API error")
 			},
 		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, nil, ghRepo)
+		c := New(afero.NewMemMapFs(), &ParamRun{}, nil, &GitHub{Repositories: ghRepo})
 
 		_, err := c.getDefaultBranch(t.Context(), slog.Default(), "owner", "repo")
 		if err == nil {
@@ -283,21 +330,62 @@ func TestController_getDefaultBranch(t *testing.T) {
 	})
 }
 
+// Mock ActionsClient
+type mockActionsClient struct {
+	listWorkflowRunsByFileNameFunc func(ctx context.Context, owner, repo, workflowFileName string, opts *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error)
+}
+
+func (m *mockActionsClient) ListWorkflowRunsByFileName(ctx context.Context, owner, repo, workflowFileName string, opts *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error) {
+	if m.listWorkflowRunsByFileNameFunc != nil {
+		return m.listWorkflowRunsByFileNameFunc(ctx, owner, repo, workflowFileName, opts)
+	}
+	return &github.WorkflowRuns{}, nil, nil
+}
+
+func (m *mockActionsClient) GetWorkflowRunByID(_ context.Context, _, _ string, _ int64) (*github.WorkflowRun, *github.Response, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (m *mockActionsClient) ListWorkflowRunArtifacts(_ context.Context, _, _ string, _ int64, _ *github.ListOptions) (*github.ArtifactList, *github.Response, error) {
+	return &github.ArtifactList{}, nil, nil
+}
+
+func (m *mockActionsClient) DownloadArtifact(_ context.Context, _, _ string, _ int64, _ int) (*url.URL, *github.Response, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
 func TestController_getRunID(t *testing.T) {
 	t.Parallel()
 
+	exec := &mockExecutor{
+		outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, args ...string) (string, error) {
+			if diff := cmp.Diff([]string{"rev-parse", "v1.0.0^{commit}"}, args); diff != "" {
+				t.Errorf("args mismatch (-want +got):\n%s", diff)
+			}
+			return "abc", nil
+		},
+	}
+
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
-		exec := &mockExecutor{
-			outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, args ...string) (string, error) {
-				// Verify command arguments
-				if args[0] != cmdRun || args[1] != "list" {
-					t.Errorf("unexpected args: %v", args)
+		actions := &mockActionsClient{
+			listWorkflowRunsByFileNameFunc: func(_ context.Context, owner, repo, workflow string, opts *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error) {
+				if owner != "test-owner" || repo != "test-repo" || workflow != "release.yaml" {
+					t.Errorf("ListWorkflowRunsByFileName() called with owner=%s, repo=%s, workflow=%s", owner, repo, workflow)
+				}
+				if opts.Branch != "v1.0.0" || opts.HeadSHA != "abc" {
+					t.Errorf("ListWorkflowRunsByFileName() called with opts=%+v", opts)
 				}
-				return "12345", nil
+				return &github.WorkflowRuns{
+					WorkflowRuns: []*github.WorkflowRun{
+						{ID: github.Ptr[int64](1), HeadBranch: github.Ptr("main"), HeadSHA: github.Ptr("abc")},
+						{ID: github.Ptr[int64](12345), HeadBranch: github.Ptr("v1.0.0"), HeadSHA: github.Ptr("abc")},
+						{ID: github.Ptr[int64](123), HeadBranch: github.Ptr("v1.0.0"), HeadSHA: github.Ptr("abc")},
+					},
+				}, nil, nil
 			},
 		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
+		c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0", Repository: "test-owner/test-repo"}, exec, &GitHub{Actions: actions})
 
 		runID, err := c.getRunID(t.Context(), slog.Default(), "release.yaml")
 		if err != nil {
@@ -308,104 +396,171 @@ func TestController_getRunID(t *testing.T) {
 		}
 	})
 
-	t.Run("error", func(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
 		t.Parallel()
-		exec := &mockExecutor{
-			outputFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, _ ...string) (string, error) {
-				return "", errors.New("gh error")
+		actions := &mockActionsClient{
+			listWorkflowRunsByFileNameFunc: func(_ context.Context, _, _, _ string, _ *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error) {
+				return &github.WorkflowRuns{
+					WorkflowRuns: []*github.WorkflowRun{
+						{ID: github.Ptr[int64](1), HeadBranch: github.Ptr("v1.0.0"), HeadSHA: github.Ptr("def")},
+					},
+				}, nil, nil
 			},
 		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
+		c := New(afero.NewMemMapFs(), &ParamRun{
+			Version:             "v1.0.0",
+			Repository:          "test-owner/test-repo",
+			RunDiscoveryTimeout: time.Second,
+		}, exec, &GitHub{Actions: actions})
 
-		_, err := c.getRunID(t.Context(), slog.Default(), "release.yaml")
-		if err == nil {
+		if _, err := c.getRunID(t.Context(), slog.Default(), "release.yaml"); err == nil {
 			t.Error("getRunID() error = nil, want error")
 		}
 	})
-}
-
-func TestController_watchRun(t *testing.T) {
-	t.Parallel()
-
-	t.Run("success", func(t *testing.T) {
-		t.Parallel()
-		exec := &mockExecutor{
-			runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
-				if name != "gh" || args[0] != cmdRun || args[1] != "watch" {
-					t.Errorf("unexpected command: %s %v", name, args)
-				}
-				return nil
-			},
-		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
-
-		err := c.watchRun(t.Context(), slog.Default(), "12345")
-		if err != nil {
-			t.Errorf("watchRun() error = %v, want nil", err)
-		}
-	})
 
 	t.Run("error", func(t *testing.T) {
 		t.Parallel()
-		exec := &mockExecutor{
-			runFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, _ ...string) error {
-				return errors.New("workflow failed")
+		actions := &mockActionsClient{
+			listWorkflowRunsByFileNameFunc: func(_ context.Context, _, _, _ string, _ *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error) {
+				return nil, nil, errors.New("API error")
 			},
 		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
+		c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0", Repository: "test-owner/test-repo"}, exec, &GitHub{Actions: actions})
 
-		err := c.watchRun(t.Context(), slog.Default(), "12345")
-		if err == nil {
-			t.Error("watchRun() error = nil, want error")
+		if _, err := c.getRunID(t.Context(), slog.Default(), "release.yaml"); err == nil {
+			t.Error("getRunID() error = nil, want error")
 		}
 	})
 }
 
-func TestController_downloadArtifacts(t *testing.T) {
+func Test_parseRepoURL(t *testing.T) {
 	t.Parallel()
-
-	t.Run("success", func(t *testing.T) {
-		t.Parallel()
-		var capturedDir string
-		exec := &mockExecutor{
-			runFunc: func(_ context.Context, _ *slog.Logger, dir string, name string, args ...string) error {
-				capturedDir = dir
-				if diff := cmp.Diff("", dir); diff != "" {
-					t.Errorf("downloadArtifacts() dir mismatch (-want +got):\n%s", diff)
-				}
-				if diff := cmp.Diff("gh", name); diff != "" {
-					t.Errorf("downloadArtifacts() name mismatch (-want +got):\n%s", diff)
-				}
-				expArgs := []string{cmdRun, "download", "12345", "--pattern", "goreleaser", "-D", "/tmp/test"}
-				if diff := cmp.Diff(expArgs, args); diff != "" {
-					t.Errorf("downloadArtifacts() args mismatch (-want +got):\n%s", diff)
+	tests := []struct {
+		name      string
+		url       string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{
+			name:      "https",
+			url:       "https://github.com/suzuki-shunsuke/rgo",
+			wantOwner: "suzuki-shunsuke",
+			wantRepo:  "rgo",
+		},
+		{
+			name:      "https with .git",
+			url:       "https://github.com/suzuki-shunsuke/rgo.git",
+			wantOwner: "suzuki-shunsuke",
+			wantRepo:  "rgo",
+		},
+		{
+			name:      "scp-like ssh",
+			url:       "git@github.com:suzuki-shunsuke/rgo.git",
+			wantOwner: "suzuki-shunsuke",
+			wantRepo:  "rgo",
+		},
+		{
+			name:      "ssh",
+			url:       "ssh://git@github.com/suzuki-shunsuke/rgo.git",
+			wantOwner: "suzuki-shunsuke",
+			wantRepo:  "rgo",
+		},
+		{
+			name:    "invalid",
+			url:     "https://github.com/suzuki-shunsuke",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			owner, repo, err := parseRepoURL(tt.url)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("parseRepoURL() error = %v, want nil", err)
 				}
-				return nil
+				return
+			}
+			if tt.wantErr {
+				t.Error("parseRepoURL() error = nil, want error")
+			}
+			if owner != tt.wantOwner || repo != tt.wantRepo {
+				t.Errorf("parseRepoURL() = %s/%s, want %s/%s", owner, repo, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestController_watchRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		runs    []*github.WorkflowRun
+		wantErr bool
+	}{
+		{
+			name: "success",
+			runs: []*github.WorkflowRun{
+				{Status: github.Ptr("queued")},
+				{Status: github.Ptr("in_progress")},
+				{Status: github.Ptr("completed"), Conclusion: github.Ptr("success")},
 			},
-		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
+		},
+		{
+			name: "failure",
+			runs: []*github.WorkflowRun{
+				{Status: github.Ptr("in_progress")},
+				{Status: github.Ptr("completed"), Conclusion: github.Ptr("failure")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cancelled",
+			runs: []*github.WorkflowRun{
+				{Status: github.Ptr("completed"), Conclusion: github.Ptr("cancelled")},
+			},
+			wantErr: true,
+		},
+	}
 
-		err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345")
-		if err != nil {
-			t.Errorf("downloadArtifacts() error = %v, want nil", err)
-		}
-		if capturedDir != "" {
-			t.Errorf(`downloadArtifacts() dir = %v, want ""`, capturedDir)
-		}
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mux, _, gh := newTestServer(t)
+			var requests int
+			mux.HandleFunc("GET /repos/owner/repo/actions/runs/12345", func(w http.ResponseWriter, _ *http.Request) {
+				run := tt.runs[min(requests, len(tt.runs)-1)]
+				requests++
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(run); err != nil {
+					t.Error(err)
+				}
+			})
+			c := New(afero.NewMemMapFs(), &ParamRun{Repository: "owner/repo"}, &mockExecutor{}, gh)
+			c.runPollInterval = time.Millisecond
 
-	t.Run("error", func(t *testing.T) {
+			err := c.watchRun(t.Context(), slog.Default(), "12345")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("watchRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != len(tt.runs) {
+				t.Errorf("requests = %d, want %d", requests, len(tt.runs))
+			}
+		})
+	}
+
+	t.Run("api error", func(t *testing.T) {
 		t.Parallel()
-		exec := &mockExecutor{
-			runFunc: func(_ context.Context, _ *slog.Logger, _ string, _ string, _ ...string) error {
-				return errors.New("download failed")
-			},
-		}
-		c := New(afero.NewMemMapFs(), &ParamRun{}, exec, nil)
+		mux, _, gh := newTestServer(t)
+		mux.HandleFunc("GET /repos/owner/repo/actions/runs/12345", func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "not found", http.StatusNotFound)
+		})
+		c := New(afero.NewMemMapFs(), &ParamRun{Repository: "owner/repo"}, &mockExecutor{}, gh)
 
-		err := c.downloadArtifacts(t.Context(), slog.Default(), "/tmp/test", "12345")
-		if err == nil {
-			t.Error("downloadArtifacts() error = nil, want error")
+		if err := c.watchRun(t.Context(), slog.Default(), "12345"); err == nil {
+			t.Error("watchRun() error = nil, want error")
 		}
 	})
 }
@@ -11,50 +11,53 @@ import (
 	"github.com/suzuki-shunsuke/rgo/pkg/config"
 )
 
-func (c *Controller) processScoop(ctx context.Context, logger *slog.Logger, cfg *config.Config, tempDir, artifactName, serverURL string) error {
-	scoopDir := filepath.Join(tempDir, artifactName, "scoop")
-	if _, err := c.fs.Stat(scoopDir); os.IsNotExist(err) {
-		logger.Info("Scoop manifest isn't found")
-		return nil
-	}
-
-	for _, scoop := range cfg.Scoops {
-		if err := c.pushScoop(ctx, logger, scoop.Repository, cfg.ProjectName, tempDir, artifactName, serverURL); err != nil {
-			return err
+func (c *Controller) processScoop(ctx context.Context, logger *slog.Logger, cfg *config.Config, scoopDir, workDir, serverURL string) error {
+	if scoopDir != "" {
+		if _, err := c.fs.Stat(scoopDir); os.IsNotExist(err) {
+			logger.Info("Scoop manifest isn't found")
+			return nil
 		}
 	}
 
-	return nil
+	return forEach(c.param.KeepGoing, cfg.Scoops, func(scoop config.Scoop) error {
+		return c.pushScoop(ctx, logger, cfg, scoop, scoopDir, workDir, serverURL)
+	})
 }
 
-func (c *Controller) pushScoop(ctx context.Context, logger *slog.Logger, repo config.Repository, projectName, tempDir, artifactName, serverURL string) error {
-	repoURL := fmt.Sprintf("%s/%s/%s", serverURL, repo.Owner, repo.Name)
+const defaultScoopCommitMsgTemplate = "Scoop update for {{ .ProjectName }} version {{ .Tag }}"
 
-	logger.Info("cloning scoop repository", "repo", repoURL)
-	repoDir := filepath.Join(tempDir, repo.Name)
-	if err := c.exec.Run(ctx, logger, tempDir, "git", "clone", "--depth", "1", repoURL); err != nil {
-		return fmt.Errorf("clone scoop repository: %w", err)
-	}
-
-	if err := c.copyScoopFiles(tempDir, artifactName, repoDir); err != nil {
+func (c *Controller) pushScoop(ctx context.Context, logger *slog.Logger, cfg *config.Config, scoop config.Scoop, scoopDir, workDir, serverURL string) error {
+	vars := c.newTemplateVars(ctx, logger, cfg)
+	repo, commitMsg, err := renderRepo(vars, scoop.Repository, scoop.CommitMsgTemplate, defaultScoopCommitMsgTemplate)
+	if err != nil {
 		return err
 	}
-
-	logger.Info("committing and pushing scoop changes")
-	if err := c.commitAndPushScoop(ctx, logger, repo, projectName, repoDir); err != nil {
+	author, err := renderAuthor(vars, scoop.CommitAuthor)
+	if err != nil {
 		return err
 	}
-
-	return nil
+	return c.pushRepo(ctx, logger, cfg, &repoTarget{
+		key:               repoKey("scoops", repo.Owner, repo.Name),
+		publisher:         "scoop",
+		repo:              repo,
+		dirName:           repo.Name,
+		defaultHeadBranch: c.defaultHeadBranch(cfg.ProjectName),
+		commitMessage:     commitMsg,
+		author:            author,
+		copyFiles: func(repoDir string) ([]string, error) {
+			return c.copyScoopFiles(scoopDir, repoDir)
+		},
+	}, scoopDir, workDir, serverURL)
 }
 
-func (c *Controller) copyScoopFiles(tempDir, artifactName, repoDir string) error {
-	scoopDir := filepath.Join(tempDir, artifactName, "scoop")
+// copyScoopFiles copies manifests to the root of the bucket and returns their names.
+func (c *Controller) copyScoopFiles(scoopDir, repoDir string) ([]string, error) {
 	entries, err := afero.ReadDir(c.fs, scoopDir)
 	if err != nil {
-		return fmt.Errorf("read scoop directory: %w", err)
+		return nil, fmt.Errorf("read scoop directory: %w", err)
 	}
 
+	var files []string
 	for _, entry := range entries {
 		if filepath.Ext(entry.Name()) != ".json" {
 			continue
@@ -62,32 +65,11 @@ func (c *Controller) copyScoopFiles(tempDir, artifactName, repoDir string) error
 		src := filepath.Join(scoopDir, entry.Name())
 		dst := filepath.Join(repoDir, entry.Name())
 		if err := c.copyFile(src, dst); err != nil {
-			return fmt.Errorf("copy scoop file: %w", err)
+			return nil, fmt.Errorf("copy scoop file: %w", err)
 		}
+		files = append(files, entry.Name())
 	}
-	return nil
-}
-
-func (c *Controller) commitAndPushScoop(ctx context.Context, logger *slog.Logger, repo config.Repository, projectName, repoDir string) error {
-	if err := c.exec.Run(ctx, logger, repoDir, "git", "add", "*.json"); err != nil {
-		return fmt.Errorf("git add: %w", err)
-	}
-
-	commitMsg := fmt.Sprintf("Scoop update for %s version %s", projectName, c.param.Version)
-	if err := c.exec.Run(ctx, logger, repoDir, "git", "commit", "-m", commitMsg); err != nil {
-		return fmt.Errorf("git commit: %w", err)
-	}
-
-	branch, err := c.getBranch(ctx, logger, repo)
-	if err != nil {
-		return err
-	}
-
-	if err := c.exec.Run(ctx, logger, repoDir, "git", "push", "origin", branch); err != nil {
-		return fmt.Errorf("git push: %w", err)
-	}
-
-	return nil
+	return files, nil
 }
 
 func (c *Controller) getBranch(ctx context.Context, logger *slog.Logger, repo config.Repository) (string, error) {
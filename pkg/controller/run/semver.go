@@ -0,0 +1,85 @@
+package run
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var semverPattern = regexp.MustCompile(`^v(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`) //nolint:gochecknoglobals
+
+// semver is a semantic version with the prefix v. e.g. v1.2.3-rc.1
+type semver struct {
+	major      uint64
+	minor      uint64
+	patch      uint64
+	prerelease []string
+}
+
+// parseSemver parses a tag as a semantic version.
+// The prefix v is required.
+func parseSemver(tag string) (*semver, error) {
+	m := semverPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return nil, fmt.Errorf("version must be a semantic version with the prefix v (e.g. v1.2.3): %s", tag)
+	}
+	v := &semver{}
+	for i, p := range []*uint64{&v.major, &v.minor, &v.patch} {
+		n, err := strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse a version %s: %w", tag, err)
+		}
+		*p = n
+	}
+	if m[4] != "" {
+		v.prerelease = strings.Split(m[4], ".")
+	}
+	return v, nil
+}
+
+// compare returns -1, 0, or 1 by the precedence of semantic versioning.
+// Build metadata is ignored.
+func (v *semver) compare(o *semver) int {
+	if c := cmp.Compare(v.major, o.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.patch, o.patch); c != 0 {
+		return c
+	}
+	// A version without prerelease has higher precedence.
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := comparePrereleaseID(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.prerelease), len(o.prerelease))
+}
+
+// comparePrereleaseID compares identifiers of prerelease.
+// Numeric identifiers have lower precedence than alphanumeric ones.
+func comparePrereleaseID(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
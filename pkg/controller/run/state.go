@@ -0,0 +1,162 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// state records finished phases of a release so that a failed release can be resumed.
+type state struct {
+	Version             string                `json:"version"`
+	TagCreated          bool                  `json:"tag_created"`
+	TagPushed           bool                  `json:"tag_pushed"`
+	RunID               string                `json:"run_id,omitempty"`
+	TempDir             string                `json:"temp_dir,omitempty"`
+	ArtifactsDownloaded bool                  `json:"artifacts_downloaded"`
+	Repositories        map[string]*repoState `json:"repositories,omitempty"`
+	Completed           bool                  `json:"completed"`
+}
+
+type repoState struct {
+	Pushed bool   `json:"pushed"`
+	Commit string `json:"commit,omitempty"`
+	// UpToDate is true if the repository already has the files, so nothing was pushed.
+	UpToDate           bool   `json:"up_to_date,omitempty"`
+	PullRequestCreated bool   `json:"pull_request_created,omitempty"`
+	PullRequestURL     string `json:"pull_request_url,omitempty"`
+}
+
+// journal persists the state to a file whenever a phase finishes.
+// If path is empty, the state is kept only in memory.
+type journal struct {
+	fs    afero.Fs
+	path  string
+	mutex sync.Mutex
+	state *state
+}
+
+const (
+	dirPermission       = 0o755
+	stateFilePermission = 0o600
+)
+
+func (c *Controller) openJournal(ctx context.Context, logger *slog.Logger) (*journal, error) {
+	j := &journal{
+		fs:    c.fs,
+		state: &state{Version: c.param.Version},
+	}
+	stateDir, err := c.getStateDir(ctx, logger)
+	if err != nil {
+		return nil, err
+	}
+	p := filepath.Join(stateDir, c.param.Version+".json")
+	if c.param.Resume {
+		if err := j.read(p); err != nil {
+			return nil, err
+		}
+		logger.Info("resuming the release", "state_file", p)
+	}
+	if !c.param.DryRun {
+		j.path = p
+	}
+	if c.param.RunID != "" {
+		j.state.RunID = c.param.RunID
+	}
+	if err := j.write(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (c *Controller) getStateDir(ctx context.Context, logger *slog.Logger) (string, error) {
+	if c.param.StateDir != "" {
+		return c.param.StateDir, nil
+	}
+	gitDir, err := c.exec.Output(ctx, logger, "", "git", "rev-parse", "--git-dir")
+	if err != nil {
+		return "", fmt.Errorf("get the .git directory: %w", err)
+	}
+	return filepath.Join(gitDir, "rgo"), nil
+}
+
+func (j *journal) read(p string) error {
+	b, err := afero.ReadFile(j.fs, p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("state file isn't found, so the release can't be resumed: %s", p)
+		}
+		return fmt.Errorf("read a state file: %w", err)
+	}
+	st := &state{}
+	if err := json.Unmarshal(b, st); err != nil {
+		return fmt.Errorf("parse a state file as JSON: %w", err)
+	}
+	if st.Version != j.state.Version {
+		return fmt.Errorf("state file is for a different version: %s", st.Version)
+	}
+	j.state = st
+	return nil
+}
+
+func (j *journal) write() error {
+	if j.path == "" {
+		return nil
+	}
+	if err := j.fs.MkdirAll(filepath.Dir(j.path), dirPermission); err != nil {
+		return fmt.Errorf("create a directory for a state file: %w", err)
+	}
+	b, err := json.MarshalIndent(j.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode a state as JSON: %w", err)
+	}
+	if err := afero.WriteFile(j.fs, j.path, b, stateFilePermission); err != nil {
+		return fmt.Errorf("write a state file: %w", err)
+	}
+	return nil
+}
+
+// get returns a copy of the current state.
+func (j *journal) get() state {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return *j.state
+}
+
+// update changes the state and writes it to the state file.
+func (j *journal) update(f func(st *state)) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	f(j.state)
+	return j.write()
+}
+
+func (j *journal) repo(key string) repoState {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if rs, ok := j.state.Repositories[key]; ok {
+		return *rs
+	}
+	return repoState{}
+}
+
+func (j *journal) updateRepo(key string, f func(rs *repoState)) error {
+	return j.update(func(st *state) {
+		if st.Repositories == nil {
+			st.Repositories = map[string]*repoState{}
+		}
+		rs, ok := st.Repositories[key]
+		if !ok {
+			rs = &repoState{}
+			st.Repositories[key] = rs
+		}
+		f(rs)
+	})
+}
@@ -0,0 +1,99 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestController_openJournal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resume", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		if err := afero.WriteFile(fs, "/state/v1.0.0.json", []byte(`{"version":"v1.0.0","tag_created":true}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c := New(fs, &ParamRun{Version: "v1.0.0", StateDir: "/state", Resume: true}, nil, nil)
+		j, err := c.openJournal(t.Context(), slog.Default())
+		if err != nil {
+			t.Fatalf("openJournal() error = %v, want nil", err)
+		}
+		if diff := cmp.Diff(state{Version: "v1.0.0", TagCreated: true}, j.get()); diff != "" {
+			t.Errorf("state mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("state file isn't found", func(t *testing.T) {
+		t.Parallel()
+		c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0", StateDir: "/state", Resume: true}, nil, nil)
+		if _, err := c.openJournal(t.Context(), slog.Default()); err == nil {
+			t.Error("openJournal() error = nil, want error")
+		}
+	})
+
+	t.Run("state file for a different version", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		if err := afero.WriteFile(fs, "/state/v1.0.0.json", []byte(`{"version":"v0.9.0"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c := New(fs, &ParamRun{Version: "v1.0.0", StateDir: "/state", Resume: true}, nil, nil)
+		if _, err := c.openJournal(t.Context(), slog.Default()); err == nil {
+			t.Error("openJournal() error = nil, want error")
+		}
+	})
+}
+
+func TestController_prepareRelease_resume(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	var commands [][]string
+	exec := &mockExecutor{
+		runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+			commands = append(commands, append([]string{name}, args...))
+			return nil
+		},
+	}
+	c := New(fs, &ParamRun{Version: "v1.0.0", StateDir: "/state"}, exec, nil)
+	j, err := c.openJournal(t.Context(), slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.journal = j
+	if err := j.update(func(st *state) {
+		st.TagCreated = true
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	runID, err := c.prepareRelease(t.Context(), slog.Default())
+	if err != nil {
+		t.Fatalf("prepareRelease() error = %v, want nil", err)
+	}
+	if runID != "" {
+		t.Errorf("prepareRelease() = %v, want empty", runID)
+	}
+	if diff := cmp.Diff([][]string{{"git", "push", "origin", "v1.0.0"}}, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+
+	b, err := afero.ReadFile(fs, "/state/v1.0.0.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `{
+  "version": "v1.0.0",
+  "tag_created": true,
+  "tag_pushed": true,
+  "artifacts_downloaded": false,
+  "completed": false
+}`
+	if diff := cmp.Diff(exp, string(b)); diff != "" {
+		t.Errorf("state file mismatch (-want +got):\n%s", diff)
+	}
+}
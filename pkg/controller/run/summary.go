@@ -0,0 +1,136 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Statuses of repositories in the summary.
+const (
+	statusPushed   = "pushed"
+	statusUpToDate = "up-to-date"
+	statusSkipped  = "skipped"
+	statusPlanned  = "planned"
+	statusFailed   = "failed"
+)
+
+// errUpToDate is returned when files in a repository are already up to date, so no commit is created.
+var errUpToDate = errors.New("already up to date")
+
+// publishResult is a row of the summary.
+type publishResult struct {
+	publisher  string
+	repository string
+	branch     string
+	// commit is the SHA of the pushed commit.
+	commit         string
+	pullRequestURL string
+	status         string
+	err            error
+}
+
+// results collects results of repositories.
+// Copies of the controller processing publishers concurrently share it.
+type results struct {
+	mutex sync.Mutex
+	rows  []*publishResult
+}
+
+func (r *results) add(row *publishResult) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.rows = append(r.rows, row)
+}
+
+func (r *results) list() []*publishResult {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*publishResult(nil), r.rows...)
+}
+
+// failed returns true if a repository of the publisher failed.
+func (r *results) failed(publisher string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, row := range r.rows {
+		if row.publisher == publisher && row.status == statusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// recordResult records the result of a repository.
+// rc is nil if the repository failed before it was resolved.
+func (c *Controller) recordResult(t *repoTarget, rc *repoConfig, status string, err error) {
+	row := &publishResult{
+		publisher: t.publisher,
+		status:    status,
+		err:       err,
+	}
+	if t.repo.Owner != "" {
+		row.repository = t.repo.Owner + "/" + t.repo.Name
+	}
+	if rc != nil {
+		row.branch = rc.headBranch
+		if rc.headOwner != "" {
+			row.repository = rc.headOwner + "/" + rc.headName
+		} else {
+			row.repository = rc.headURL
+		}
+	}
+	if err != nil {
+		row.status = statusFailed
+	}
+	rs := c.journal.repo(t.key)
+	row.commit = rs.Commit
+	row.pullRequestURL = rs.PullRequestURL
+	c.results.add(row)
+}
+
+// forEach calls f for each item.
+// It stops at the first error unless keepGoing is true, then all errors are joined.
+func forEach[T any](keepGoing bool, items []T, f func(item T) error) error {
+	var errs []error
+	for _, item := range items {
+		if err := f(item); err != nil {
+			if !keepGoing {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// printSummary prints results of repositories as a table.
+func (c *Controller) printSummary() {
+	rows := c.results.list()
+	if len(rows) == 0 || c.param.Stdout == nil {
+		return
+	}
+	w := tabwriter.NewWriter(c.param.Stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(w, "PUBLISHER\tREPOSITORY\tBRANCH\tCOMMIT/PR\tSTATUS\tERROR")
+	for _, row := range rows {
+		ref := row.pullRequestURL
+		if ref == "" {
+			ref = row.commit
+		}
+		var msg string
+		if row.err != nil {
+			msg = strings.ReplaceAll(row.err.Error(), "\n", "; ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", row.publisher, dash(row.repository), dash(row.branch), dash(ref), row.status, dash(msg))
+	}
+	_ = w.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
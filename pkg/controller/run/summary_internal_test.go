@@ -0,0 +1,84 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func Test_forEach(t *testing.T) {
+	t.Parallel()
+	f := func(called *[]string) func(item string) error {
+		return func(item string) error {
+			*called = append(*called, item)
+			if item == "b" {
+				return errors.New("b failed")
+			}
+			return nil
+		}
+	}
+
+	var called []string
+	if err := forEach(false, []string{"a", "b", "c"}, f(&called)); err == nil || err.Error() != "b failed" {
+		t.Errorf("forEach() error = %v, want b failed", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, called); diff != "" {
+		t.Errorf("called items mismatch (-want +got):\n%s", diff)
+	}
+
+	called = nil
+	if err := forEach(true, []string{"a", "b", "c"}, f(&called)); err == nil || err.Error() != "b failed" {
+		t.Errorf("forEach() error = %v, want b failed", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, called); diff != "" {
+		t.Errorf("called items mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestController_printSummary(t *testing.T) {
+	t.Parallel()
+	stdout := &bytes.Buffer{}
+	c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.0.0", Stdout: stdout}, &mockExecutor{}, nil)
+	if err := c.journal.updateRepo("scoop", func(rs *repoState) {
+		rs.Pushed = true
+		rs.Commit = "abc123"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.journal.updateRepo("homebrew", func(rs *repoState) {
+		rs.Pushed = true
+		rs.PullRequestURL = "https://github.com/octocat/homebrew-tap/pull/1"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c.recordResult(&repoTarget{key: "scoop", publisher: "scoop"}, &repoConfig{headOwner: "octocat", headName: "scoop-bucket", headBranch: "main"}, statusPushed, nil)
+	c.recordResult(&repoTarget{key: "homebrew", publisher: "homebrew"}, &repoConfig{headOwner: "octocat", headName: "homebrew-tap", headBranch: "foo-v1.0.0"}, statusPushed, nil)
+	c.recordResult(&repoTarget{key: "winget", publisher: "winget", repo: config.Repository{Owner: "octocat", Name: "winget-pkgs"}}, nil, statusPushed, errors.New("clone failed\nexit status 128"))
+	// The AUR repository was already pushed before the release is resumed.
+	if err := c.journal.updateRepo("aurs/foo-bin", func(rs *repoState) {
+		rs.Pushed = true
+		rs.Commit = "def456"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	aur := config.AUR{Name: "foo-bin", GitURL: "ssh://aur@aur.archlinux.org/foo-bin.git"}
+	if err := c.pushAUR(t.Context(), slog.Default(), &config.Config{ProjectName: "foo"}, aur, "/dist/aur", "/work"); err != nil {
+		t.Fatalf("pushAUR() error = %v, want nil", err)
+	}
+	c.printSummary()
+
+	exp := `PUBLISHER  REPOSITORY                               BRANCH      COMMIT/PR                                       STATUS   ERROR
+scoop      octocat/scoop-bucket                     main        abc123                                          pushed   -
+homebrew   octocat/homebrew-tap                     foo-v1.0.0  https://github.com/octocat/homebrew-tap/pull/1  pushed   -
+winget     octocat/winget-pkgs                      -           -                                               failed   clone failed; exit status 128
+aur        ssh://aur@aur.archlinux.org/foo-bin.git  master      def456                                          skipped  -
+`
+	if diff := cmp.Diff(exp, stdout.String()); diff != "" {
+		t.Errorf("summary mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,150 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Checks before creating a tag. Each of them can be skipped by --force.
+const (
+	forceAll          = "all"
+	forceDirty        = "dirty"
+	forceUnpushed     = "unpushed"
+	forceVersion      = "version"
+	forceVersionOrder = "version-order"
+	forceExistingTag  = "existing-tag"
+)
+
+// forced returns true if a check is skipped by --force.
+func (c *Controller) forced(check string) bool {
+	return slices.Contains(c.param.Force, check) || slices.Contains(c.param.Force, forceAll)
+}
+
+// existingTag tells where the released tag already exists.
+// They're set only when the check is forced.
+type existingTag struct {
+	local  bool
+	remote bool
+}
+
+// checkBeforeTag checks the local checkout and the version before creating a tag.
+// All failed checks are returned at once.
+func (c *Controller) checkBeforeTag(ctx context.Context, logger *slog.Logger) (*existingTag, error) {
+	for _, check := range c.param.Force {
+		if !slices.Contains([]string{forceAll, forceDirty, forceUnpushed, forceVersion, forceVersionOrder, forceExistingTag}, check) {
+			return nil, fmt.Errorf("unknown check is specified by --force: %s", check)
+		}
+	}
+	var errs []error
+	if !c.forced(forceDirty) {
+		if out, err := c.exec.Output(ctx, logger, "", "git", "status", "--porcelain"); err != nil {
+			errs = append(errs, fmt.Errorf("check if the working tree is clean: %w", err))
+		} else if out != "" {
+			errs = append(errs, forceError(forceDirty, "the working tree isn't clean. Commit or stash changes"))
+		}
+	}
+	if !c.forced(forceUnpushed) {
+		if err := c.checkHEADOnDefaultBranch(ctx, logger); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	version, err := parseSemver(c.param.Version)
+	if err != nil && !c.forced(forceVersion) {
+		errs = append(errs, fmt.Errorf("%w (--force %s)", err, forceVersion))
+	}
+
+	localTags, err := c.exec.Output(ctx, logger, "", "git", "tag", "--list")
+	if err != nil {
+		return nil, errors.Join(append(errs, fmt.Errorf("list local tags: %w", err))...)
+	}
+	remoteTags, err := c.exec.Output(ctx, logger, "", "git", "ls-remote", "--tags", "origin")
+	if err != nil {
+		return nil, errors.Join(append(errs, fmt.Errorf("list remote tags: %w", err))...)
+	}
+	local := strings.Fields(localTags)
+	remote := parseLsRemoteTags(remoteTags)
+
+	if version != nil && !c.forced(forceVersionOrder) {
+		tags := slices.DeleteFunc(slices.Concat(local, remote), func(tag string) bool {
+			return tag == c.param.Version
+		})
+		if latest := latestTag(tags); latest != "" {
+			if v, _ := parseSemver(latest); version.compare(v) <= 0 {
+				errs = append(errs, forceError(forceVersionOrder, fmt.Sprintf("version %s must be greater than the latest tag %s", c.param.Version, latest)))
+			}
+		}
+	}
+
+	exists := &existingTag{
+		local:  slices.Contains(local, c.param.Version),
+		remote: slices.Contains(remote, c.param.Version),
+	}
+	if (exists.local || exists.remote) && !c.forced(forceExistingTag) {
+		errs = append(errs, forceError(forceExistingTag, "the tag "+c.param.Version+" already exists"))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return exists, nil
+}
+
+func forceError(check, msg string) error {
+	return fmt.Errorf("%s (--force %s)", msg, check)
+}
+
+// checkHEADOnDefaultBranch checks if HEAD is included in the default branch of the remote origin.
+func (c *Controller) checkHEADOnDefaultBranch(ctx context.Context, logger *slog.Logger) error {
+	owner, repo, err := c.getRepository(ctx, logger)
+	if err != nil {
+		return err
+	}
+	branch, err := c.getDefaultBranch(ctx, logger, owner, repo)
+	if err != nil {
+		return err
+	}
+	if err := c.exec.Run(ctx, logger, "", "git", "fetch", "--quiet", "origin", branch); err != nil {
+		return fmt.Errorf("fetch the default branch: %w", err)
+	}
+	if err := c.exec.Run(ctx, logger, "", "git", "merge-base", "--is-ancestor", "HEAD", "FETCH_HEAD"); err != nil {
+		return forceError(forceUnpushed, "HEAD isn't pushed to the default branch "+branch)
+	}
+	return nil
+}
+
+// parseLsRemoteTags parses the output of git ls-remote --tags and returns tag names.
+func parseLsRemoteTags(out string) []string {
+	var tags []string
+	for line := range strings.SplitSeq(out, "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok || strings.HasSuffix(ref, "^{}") {
+			continue
+		}
+		if tag, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// latestTag returns the greatest tag of semantic versions.
+// Tags which aren't semantic versions are ignored.
+func latestTag(tags []string) string {
+	var latest string
+	var latestVersion *semver
+	for _, tag := range tags {
+		v, err := parseSemver(tag)
+		if err != nil {
+			continue
+		}
+		if latestVersion == nil || v.compare(latestVersion) > 0 {
+			latest = tag
+			latestVersion = v
+		}
+	}
+	return latest
+}
@@ -0,0 +1,156 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+)
+
+func TestController_checkBeforeTag(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name       string
+		version    string
+		force      []string
+		status     string
+		notPushed  bool
+		localTags  string
+		remoteTags string
+		exp        *existingTag
+		errs       []string
+	}{
+		{
+			name:       "pass",
+			version:    "v1.1.0",
+			localTags:  "v0.9.0\nv1.0.0",
+			remoteTags: "abc\trefs/tags/v1.0.0\nabc\trefs/tags/v1.0.0^{}\nabc\trefs/tags/v1.1.0-rc.1",
+			exp:        &existingTag{},
+		},
+		{
+			name:       "fail",
+			version:    "v1.0.0",
+			status:     " M main.go",
+			notPushed:  true,
+			localTags:  "v1.0.0",
+			remoteTags: "abc\trefs/tags/v1.1.0",
+			errs: []string{
+				"the working tree isn't clean. Commit or stash changes (--force dirty)",
+				"HEAD isn't pushed to the default branch main (--force unpushed)",
+				"version v1.0.0 must be greater than the latest tag v1.1.0 (--force version-order)",
+				"the tag v1.0.0 already exists (--force existing-tag)",
+			},
+		},
+		{
+			name:    "invalid version",
+			version: "1.0.0",
+			errs: []string{
+				"version must be a semantic version with the prefix v (e.g. v1.2.3): 1.0.0 (--force version)",
+			},
+		},
+		{
+			name:       "force",
+			version:    "v1.0.0",
+			force:      []string{"all"},
+			status:     " M main.go",
+			notPushed:  true,
+			localTags:  "v1.0.0",
+			remoteTags: "abc\trefs/tags/v1.0.0\nabc\trefs/tags/v1.1.0",
+			exp:        &existingTag{local: true, remote: true},
+		},
+		{
+			name:    "unknown check",
+			version: "v1.0.0",
+			force:   []string{"foo"},
+			errs:    []string{"unknown check is specified by --force: foo"},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			exec := &mockExecutor{
+				runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+					if name+" "+strings.Join(args, " ") == "git merge-base --is-ancestor HEAD FETCH_HEAD" && d.notPushed {
+						return errors.New("exit status 1")
+					}
+					return nil
+				},
+				outputFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) (string, error) {
+					switch name + " " + strings.Join(args, " ") {
+					case "git status --porcelain":
+						return d.status, nil
+					case "git tag --list":
+						return d.localTags, nil
+					case "git ls-remote --tags origin":
+						return d.remoteTags, nil
+					}
+					return "", nil
+				},
+			}
+			c := New(afero.NewMemMapFs(), &ParamRun{Version: d.version, Force: d.force, Repository: "octocat/foo"}, exec, &GitHub{
+				Repositories: &mockRepositoriesClient{
+					getFunc: func(_ context.Context, _, _ string) (*github.Repository, *github.Response, error) {
+						return &github.Repository{DefaultBranch: github.Ptr("main")}, nil, nil
+					},
+				},
+			})
+			exists, err := c.checkBeforeTag(t.Context(), slog.Default())
+			if len(d.errs) > 0 {
+				if err == nil {
+					t.Fatal("checkBeforeTag() error = nil, want error")
+				}
+				if diff := cmp.Diff(d.errs, strings.Split(err.Error(), "\n")); diff != "" {
+					t.Errorf("errors mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkBeforeTag() error = %v, want nil", err)
+			}
+			if diff := cmp.Diff(d.exp, exists, cmp.AllowUnexported(existingTag{})); diff != "" {
+				t.Errorf("checkBeforeTag() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_semver_compare(t *testing.T) {
+	t.Parallel()
+	// Ordered by precedence.
+	versions := []string{
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.0.1",
+		"v1.10.0",
+		"v2.0.0",
+	}
+	for i := 1; i < len(versions); i++ {
+		a, err := parseSemver(versions[i-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := parseSemver(versions[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.compare(b) != -1 || b.compare(a) != 1 {
+			t.Errorf("%s must be less than %s", versions[i-1], versions[i])
+		}
+	}
+	for _, v := range []string{"1.0.0", "v1.0", "v01.0.0", "v1.0.0-"} {
+		if _, err := parseSemver(v); err == nil {
+			t.Errorf("parseSemver(%q) error = nil, want error", v)
+		}
+	}
+}
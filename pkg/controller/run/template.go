@@ -0,0 +1,134 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+// templateVars are variables of templates such as commit_msg_template and repository.branch.
+// They are compatible with GoReleaser's template variables.
+type templateVars struct {
+	ProjectName string
+	// Tag is the released tag. e.g. v1.2.3-rc.1
+	Tag string
+	// Version is the tag without the prefix v. e.g. 1.2.3-rc.1
+	Version string
+	// RawVersion is the version without the prerelease and build metadata. e.g. 1.2.3
+	RawVersion string
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Date       string
+	Timestamp  int64
+	Env        map[string]string
+	// PackageIdentifier is the identifier of a winget package. It's set only for winget.
+	PackageIdentifier string
+
+	previousTag     func() string
+	previousTagOnce sync.Once
+	previousTagVal  string
+}
+
+// PreviousTag returns the tag before the released tag.
+// It's resolved only when it's used in templates because it runs git.
+func (v *templateVars) PreviousTag() string {
+	v.previousTagOnce.Do(func() {
+		if v.previousTag != nil {
+			v.previousTagVal = v.previousTag()
+		}
+	})
+	return v.previousTagVal
+}
+
+func (c *Controller) newTemplateVars(ctx context.Context, logger *slog.Logger, cfg *config.Config) *templateVars {
+	now := time.Now().UTC()
+	vars := &templateVars{
+		ProjectName: cfg.ProjectName,
+		Tag:         c.param.Version,
+		Version:     strings.TrimPrefix(c.param.Version, "v"),
+		Date:        now.Format(time.RFC3339),
+		Timestamp:   now.Unix(),
+		Env:         map[string]string{},
+		previousTag: func() string {
+			tag, err := c.exec.Output(ctx, logger, "", "git", "describe", "--tags", "--abbrev=0", c.param.Version+"^")
+			if err != nil {
+				logger.Warn("get the previous tag", "error", err)
+				return ""
+			}
+			return strings.TrimSpace(tag)
+		},
+	}
+	vars.RawVersion, vars.Prerelease, _ = strings.Cut(strings.SplitN(vars.Version, "+", 2)[0], "-") //nolint:mnd
+	nums := strings.SplitN(vars.RawVersion, ".", 3)                                                 //nolint:mnd
+	for i, p := range []*uint64{&vars.Major, &vars.Minor, &vars.Patch} {
+		if i < len(nums) {
+			*p, _ = strconv.ParseUint(nums[i], 10, 64)
+		}
+	}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		vars.Env[k] = v
+	}
+	return vars
+}
+
+// renderTemplate renders a Go template with variables.
+func renderTemplate(name, text string, vars *templateVars) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse a template %s: %w", name, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, vars); err != nil {
+		return "", fmt.Errorf("render a template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// renderRepo renders the branch of a repository and the commit message.
+// If msgTemplate is empty, defaultMsgTemplate is used.
+func renderRepo(vars *templateVars, repo config.Repository, msgTemplate, defaultMsgTemplate string) (config.Repository, string, error) {
+	branch, err := renderTemplate("repository.branch", repo.Branch, vars)
+	if err != nil {
+		return repo, "", err
+	}
+	repo.Branch = branch
+	if msgTemplate == "" {
+		msgTemplate = defaultMsgTemplate
+	}
+	msg, err := renderTemplate("commit_msg_template", msgTemplate, vars)
+	if err != nil {
+		return repo, "", err
+	}
+	return repo, msg, nil
+}
+
+// renderAuthor renders the commit author and the signing key.
+func renderAuthor(vars *templateVars, author config.CommitAuthor) (config.CommitAuthor, error) {
+	for name, p := range map[string]*string{
+		"commit_author.name":        &author.Name,
+		"commit_author.email":       &author.Email,
+		"commit_author.signing.key": &author.Signing.Key,
+	} {
+		s, err := renderTemplate(name, *p, vars)
+		if err != nil {
+			return author, err
+		}
+		*p = s
+	}
+	return author, nil
+}
@@ -0,0 +1,111 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_newTemplateVars(t *testing.T) {
+	t.Parallel()
+	exec := &mockExecutor{
+		outputFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) (string, error) {
+			if diff := cmp.Diff([]string{"git", "describe", "--tags", "--abbrev=0", "v1.2.3-rc.1+build^"}, append([]string{name}, args...)); diff != "" {
+				t.Errorf("command mismatch (-want +got):\n%s", diff)
+			}
+			return "v1.2.2\n", nil
+		},
+	}
+	c := New(afero.NewMemMapFs(), &ParamRun{Version: "v1.2.3-rc.1+build"}, exec, nil)
+	vars := c.newTemplateVars(t.Context(), slog.Default(), &config.Config{ProjectName: "foo"})
+	got := map[string]any{
+		"ProjectName": vars.ProjectName,
+		"Tag":         vars.Tag,
+		"Version":     vars.Version,
+		"RawVersion":  vars.RawVersion,
+		"Major":       vars.Major,
+		"Minor":       vars.Minor,
+		"Patch":       vars.Patch,
+		"Prerelease":  vars.Prerelease,
+		"PreviousTag": vars.PreviousTag(),
+	}
+	exp := map[string]any{
+		"ProjectName": "foo",
+		"Tag":         "v1.2.3-rc.1+build",
+		"Version":     "1.2.3-rc.1+build",
+		"RawVersion":  "1.2.3",
+		"Major":       uint64(1),
+		"Minor":       uint64(2),
+		"Patch":       uint64(3),
+		"Prerelease":  "rc.1",
+		"PreviousTag": "v1.2.2",
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("newTemplateVars() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_renderRepo(t *testing.T) {
+	t.Parallel()
+	vars := &templateVars{
+		ProjectName: "foo",
+		Tag:         "v1.0.0",
+		Version:     "1.0.0",
+		Major:       1,
+		previousTag: func() string {
+			return "v0.9.0"
+		},
+	}
+	tests := []struct {
+		name        string
+		repo        config.Repository
+		msgTemplate string
+		expBranch   string
+		expMsg      string
+		wantErr     bool
+	}{
+		{
+			name:      "default",
+			repo:      config.Repository{Branch: "main"},
+			expBranch: "main",
+			expMsg:    "Update foo to v1.0.0",
+		},
+		{
+			name:        "template",
+			repo:        config.Repository{Branch: "{{ .ProjectName }}-{{ .Version }}"},
+			msgTemplate: "{{ .ProjectName }}: {{ .PreviousTag }} -> {{ .Tag }} (v{{ .Major }})",
+			expBranch:   "foo-1.0.0",
+			expMsg:      "foo: v0.9.0 -> v1.0.0 (v1)",
+		},
+		{
+			name:        "unknown variable",
+			msgTemplate: "{{ .Foo }}",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			repo, msg, err := renderRepo(vars, tt.repo, tt.msgTemplate, "Update {{ .ProjectName }} to {{ .Tag }}")
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("renderRepo() error = %v, want nil", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("renderRepo() error = nil, want error")
+			}
+			if repo.Branch != tt.expBranch {
+				t.Errorf("branch = %q, want %q", repo.Branch, tt.expBranch)
+			}
+			if msg != tt.expMsg {
+				t.Errorf("commit message = %q, want %q", msg, tt.expMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v90/github"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+)
+
+func TestController_pushWinget(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		web         bool
+		expCommands []string
+		expPR       *github.CreatePullRequest
+		expState    repoState
+	}{
+		{
+			name: "api",
+			expCommands: []string{
+				"git init winget-pkgs",
+				"git remote add origin https://github.com/microsoft/winget-pkgs",
+				"git fetch --depth=1 origin master",
+				"git checkout -b foo-1.0.0 origin/master",
+				"git add " + filepath.Join("manifests", "o", "octocat", "foo", "1.0.0", "octocat.foo.yaml"),
+				"git commit -m Update octocat.foo to v1.0.0",
+				"git remote add fork https://github.com/octocat/winget-pkgs",
+				"git push fork foo-1.0.0",
+			},
+			expPR: &github.CreatePullRequest{
+				Title: github.Ptr("New version: octocat.foo v1.0.0"),
+				Head:  "octocat:foo-1.0.0",
+				Base:  "master",
+				Body:  github.Ptr("- [ ] Have you signed the CLA?\n"),
+				Draft: github.Ptr(true),
+			},
+			expState: repoState{
+				Pushed:             true,
+				PullRequestCreated: true,
+				PullRequestURL:     "https://github.com/microsoft/winget-pkgs/pull/1",
+			},
+		},
+		{
+			name: "web",
+			web:  true,
+			expCommands: []string{
+				"git init winget-pkgs",
+				"git remote add origin https://github.com/microsoft/winget-pkgs",
+				"git fetch --depth=1 origin master",
+				"git checkout -b foo-1.0.0 origin/master",
+				"git add " + filepath.Join("manifests", "o", "octocat", "foo", "1.0.0", "octocat.foo.yaml"),
+				"git commit -m Update octocat.foo to v1.0.0",
+				"git remote add fork https://github.com/octocat/winget-pkgs",
+				"git push fork foo-1.0.0",
+				"gh pr create --repo microsoft/winget-pkgs --base master --head octocat:foo-1.0.0 --title New version: octocat.foo v1.0.0 --body - [ ] Have you signed the CLA?\n --web",
+			},
+			expState: repoState{
+				Pushed:             true,
+				PullRequestCreated: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "/dist/winget/manifests/o/octocat/foo/1.0.0/octocat.foo.yaml", []byte("{}"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var commands []string
+			exec := &mockExecutor{
+				runFunc: func(_ context.Context, _ *slog.Logger, _ string, name string, args ...string) error {
+					commands = append(commands, name+" "+strings.Join(args, " "))
+					if name == "git" && args[0] == "checkout" {
+						// winget-pkgs has a pull request template.
+						if err := afero.WriteFile(fs, "/work/winget-pkgs/.github/PULL_REQUEST_TEMPLATE.md", []byte("- [ ] Have you signed the CLA?\n"), 0o644); err != nil {
+							t.Fatal(err)
+						}
+					}
+					return nil
+				},
+				outputFunc: outputChanged,
+			}
+			var created *github.CreatePullRequest
+			c := New(fs, &ParamRun{Version: "v1.0.0", Web: tt.web}, exec, &GitHub{
+				PullRequests: &mockPullRequestsClient{
+					createFunc: func(_ context.Context, _, _ string, body github.CreatePullRequest) (*github.PullRequest, *github.Response, error) {
+						created = &body
+						return &github.PullRequest{
+							Number:  github.Ptr(1),
+							HTMLURL: github.Ptr("https://github.com/microsoft/winget-pkgs/pull/1"),
+						}, nil, nil
+					},
+				},
+			})
+			cfg := &config.Config{ProjectName: "foo"}
+			winget := config.Winget{
+				Publisher: "octocat",
+				Repository: config.Repository{
+					Owner:  "octocat",
+					Name:   "winget-pkgs",
+					Branch: "foo-{{.Version}}",
+					PullRequest: config.PullRequest{
+						Draft: true,
+						Base: config.PullRequestBase{
+							Owner:  "microsoft",
+							Branch: "master",
+						},
+					},
+				},
+			}
+			if err := c.pushWinget(t.Context(), slog.Default(), cfg, winget, "/dist/winget/manifests", "/work", "https://github.com"); err != nil {
+				t.Fatalf("pushWinget() error = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.expCommands, commands); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.expPR, created); diff != "" {
+				t.Errorf("pull request mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.expState, c.journal.repo(repoKey("winget", "octocat", "winget-pkgs"))); diff != "" {
+				t.Errorf("state mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+	"github.com/suzuki-shunsuke/rgo/pkg/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+// validateConfig validates publishers in .goreleaser.yaml and returns publishers found in the file.
+func validateConfig(ps *problems, root *yaml.Node) []string {
+	var publishers []string
+	for _, pk := range config.PublisherKeys {
+		seq := yamlnode.Get(root, pk.Key)
+		if seq == nil || seq.Kind != yaml.SequenceNode || len(seq.Content) == 0 {
+			continue
+		}
+		if !slices.Contains(publishers, pk.Publisher) {
+			publishers = append(publishers, pk.Publisher)
+		}
+		for i, item := range seq.Content {
+			if item.Kind != yaml.MappingNode {
+				ps.add(item, "%s[%d] must be a mapping", pk.Key, i)
+				continue
+			}
+			validatePublisher(ps, fmt.Sprintf("%s[%d]", pk.Key, i), pk.Publisher, item)
+		}
+	}
+	return publishers
+}
+
+func validatePublisher(ps *problems, name, publisher string, item *yaml.Node) {
+	if v := yamlnode.Get(item, "skip_upload"); v == nil {
+		ps.add(item, "%s: skip_upload must be true. Otherwise GoReleaser pushes files", name)
+	} else if v.Value != "true" {
+		ps.add(v, "%s: skip_upload must be true. Otherwise GoReleaser pushes files", name)
+	}
+	validateTemplate(ps, name+".commit_msg_template", yamlnode.Get(item, "commit_msg_template"))
+
+	switch publisher {
+	case "aur":
+		if v := yamlnode.Get(item, "git_url"); v == nil || v.Value == "" {
+			ps.add(item, "%s: git_url is required", name)
+		}
+		if v := yamlnode.Get(item, "name"); v != nil && !filepath.IsLocal(v.Value) {
+			ps.add(v, "%s: name is invalid: %s", name, v.Value)
+		}
+		if v := yamlnode.Get(item, "directory"); v != nil && v.Value != "" && !filepath.IsLocal(v.Value) {
+			ps.add(v, "%s: directory must be a relative path in the repository: %s", name, v.Value)
+		}
+		return
+	case "winget":
+		if v := yamlnode.Get(item, "publisher"); v == nil || v.Value == "" {
+			ps.add(item, "%s: publisher is required", name)
+		}
+	}
+
+	repo := yamlnode.Get(item, "repository")
+	if repo == nil {
+		ps.add(item, "%s: repository is required", name)
+		return
+	}
+	for _, key := range []string{"owner", "name"} {
+		if v := yamlnode.Get(repo, key); v == nil || v.Value == "" {
+			ps.add(repo, "%s: repository.%s is required", name, key)
+		}
+	}
+	validateTemplate(ps, name+".repository.branch", yamlnode.Get(repo, "branch"))
+}
+
+// validateTemplate checks if a template can be parsed.
+func validateTemplate(ps *problems, name string, node *yaml.Node) {
+	if node == nil || !strings.Contains(node.Value, "{{") {
+		return
+	}
+	if _, err := template.New(name).Parse(node.Value); err != nil {
+		ps.add(node, "%s: parse a template: %v", name, err)
+	}
+}
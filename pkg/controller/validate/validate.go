@@ -0,0 +1,138 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+	"github.com/suzuki-shunsuke/rgo/pkg/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+type Controller struct {
+	fs    afero.Fs
+	param *Param
+}
+
+type Param struct {
+	ConfigFilePath    string
+	RgoConfigFilePath string
+	// Workflow is the file name or path of the release workflow.
+	// A file name is resolved in .github/workflows.
+	Workflow string
+	// Output is the output format (text or json).
+	Output string
+	Stdout io.Writer
+}
+
+func New(fs afero.Fs, param *Param) *Controller {
+	return &Controller{
+		fs:    fs,
+		param: param,
+	}
+}
+
+// Problem is a validation error at a position of a file.
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (p *Problem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+}
+
+// problems collects problems of a file.
+type problems struct {
+	file string
+	list []*Problem
+}
+
+func (ps *problems) add(node *yaml.Node, format string, a ...any) {
+	ps.list = append(ps.list, &Problem{
+		File:    ps.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// Validate validates .goreleaser.yaml, .rgo.yaml, and the release workflow, and outputs problems.
+// It returns an error if any problem is found.
+func (c *Controller) Validate(_ context.Context, logger *slog.Logger) error {
+	switch c.param.Output {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("output must be text or json: %s", c.param.Output)
+	}
+	list, err := c.validate()
+	if err != nil {
+		return err
+	}
+	if err := c.output(list); err != nil {
+		return err
+	}
+	if len(list) > 0 {
+		return fmt.Errorf("%d problems are found", len(list))
+	}
+	logger.Info("no problem is found")
+	return nil
+}
+
+func (c *Controller) validate() ([]*Problem, error) {
+	cfgPath, err := config.FilePath(c.fs, c.param.ConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("find a config file: %w", err)
+	}
+	// Read the config file with config.Read to check types of fields.
+	cfg, err := config.Read(c.fs, cfgPath)
+	if err != nil {
+		return []*Problem{{File: cfgPath, Message: err.Error()}}, nil
+	}
+	rgo, err := config.ReadRgo(c.fs, c.param.RgoConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read a rgo config file: %w", err)
+	}
+	cfg.Rgo = *rgo
+
+	cfgNode, err := yamlnode.Read(c.fs, cfgPath)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	ps := &problems{file: cfgPath}
+	publishers := validateConfig(ps, cfgNode.Content[0])
+	list := ps.list
+
+	workflowPath := config.WorkflowFilePath(c.param.Workflow)
+	workflowNode, err := yamlnode.Read(c.fs, workflowPath)
+	if err != nil {
+		return append(list, &Problem{File: workflowPath, Message: err.Error()}), nil
+	}
+	ps = &problems{file: workflowPath}
+	validateWorkflow(ps, workflowNode.Content[0], publishers, &cfg.Rgo.Artifacts)
+	return append(list, ps.list...), nil
+}
+
+func (c *Controller) output(list []*Problem) error {
+	if c.param.Output == "json" {
+		if list == nil {
+			list = []*Problem{}
+		}
+		enc := json.NewEncoder(c.param.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(list); err != nil {
+			return fmt.Errorf("encode problems as JSON: %w", err)
+		}
+		return nil
+	}
+	for _, p := range list {
+		fmt.Fprintln(c.param.Stdout, p.String())
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package validate
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestController_Validate(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name     string
+		cfg      string
+		workflow string
+		exp      string
+		isErr    bool
+	}{
+		{
+			name: "valid",
+			cfg: `brews:
+  - skip_upload: true
+    repository:
+      owner: octocat
+      name: homebrew-foo
+winget:
+  - skip_upload: true
+    publisher: octocat
+    repository:
+      owner: octocat
+      name: winget-pkgs
+`,
+			workflow: `jobs:
+  release:
+    steps:
+      - run: goreleaser release --clean
+      - uses: actions/upload-artifact@v4
+        with:
+          name: goreleaser
+          path: |
+            dist/homebrew/*.rb
+            dist/winget
+`,
+		},
+		{
+			name: "invalid",
+			cfg: `brews:
+  - repository:
+      owner: octocat
+    commit_msg_template: "{{ .Tag"
+scoops:
+  - skip_upload: false
+    repository:
+      owner: octocat
+      name: scoop-bucket
+winget:
+  - skip_upload: true
+    repository:
+      owner: octocat
+      name: winget-pkgs
+aurs:
+  - skip_upload: true
+    name: ../foo
+`,
+			workflow: `jobs:
+  release:
+    steps:
+      - uses: actions/upload-artifact@v4
+        with:
+          name: goreleaser
+          path: |
+            dist/homebrew/*.rb
+            dist/scoop/*.json
+`,
+			isErr: true,
+			exp: `.goreleaser.yaml:2:5: brews[0]: skip_upload must be true. Otherwise GoReleaser pushes files
+.goreleaser.yaml:4:26: brews[0].commit_msg_template: parse a template: template: brews[0].commit_msg_template:1: unclosed action
+.goreleaser.yaml:3:7: brews[0]: repository.name is required
+.goreleaser.yaml:6:18: scoops[0]: skip_upload must be true. Otherwise GoReleaser pushes files
+.goreleaser.yaml:11:5: winget[0]: publisher is required
+.goreleaser.yaml:16:5: aurs[0]: git_url is required
+.goreleaser.yaml:17:11: aurs[0]: name is invalid: ../foo
+.github/workflows/release.yaml:7:17: path of the artifact goreleaser doesn't include winget/manifests for winget
+.github/workflows/release.yaml:7:17: path of the artifact goreleaser doesn't include aur for aur
+`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, ".goreleaser.yaml", []byte(d.cfg), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := afero.WriteFile(fs, ".github/workflows/release.yaml", []byte(d.workflow), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			stdout := &bytes.Buffer{}
+			c := New(fs, &Param{Workflow: "release.yaml", Stdout: stdout})
+			err := c.Validate(t.Context(), slog.Default())
+			if err != nil {
+				if !d.isErr {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+			} else if d.isErr {
+				t.Fatal("Validate() error = nil, want error")
+			}
+			if diff := cmp.Diff(d.exp, stdout.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_coversPath(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		lines []string
+		p     string
+		exp   bool
+	}{
+		{
+			name:  "glob",
+			lines: []string{"dist/homebrew/*.rb", "dist/scoop/*.json"},
+			p:     "homebrew",
+			exp:   true,
+		},
+		{
+			name:  "directory",
+			lines: []string{"dist/winget/manifests", "dist/metadata.json"},
+			p:     "winget/manifests",
+			exp:   true,
+		},
+		{
+			name:  "root is stripped",
+			lines: []string{"dist/homebrew/*.rb"},
+			p:     "homebrew",
+			exp:   false,
+		},
+		{
+			name:  "single directory is stripped",
+			lines: []string{"dist/homebrew"},
+			p:     "homebrew",
+			exp:   false,
+		},
+		{
+			name:  "single directory with a trailing slash is stripped",
+			lines: []string{"dist/scoop/"},
+			p:     "scoop",
+			exp:   false,
+		},
+		{
+			name:  "dist isn't the root",
+			lines: []string{"dist/homebrew/*.rb", "README.md"},
+			p:     "homebrew",
+			exp:   false,
+		},
+		{
+			name:  "exclusion is ignored",
+			lines: []string{"dist/homebrew", "!dist/homebrew/*.txt", "dist/metadata.json"},
+			p:     "homebrew",
+			exp:   true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := coversPath(d.lines, d.p); got != d.exp {
+				t.Errorf("coversPath() = %v, want %v", got, d.exp)
+			}
+		})
+	}
+}
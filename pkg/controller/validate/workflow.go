@@ -0,0 +1,144 @@
+package validate
+
+import (
+	"path"
+	"strings"
+
+	"github.com/suzuki-shunsuke/rgo/pkg/config"
+	"github.com/suzuki-shunsuke/rgo/pkg/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultUploadArtifactName is the default artifact name of actions/upload-artifact.
+	defaultUploadArtifactName = "artifact"
+	// distDir is GoReleaser's output directory.
+	distDir = "dist"
+)
+
+type uploadStep struct {
+	name string
+	step *yaml.Node
+	path *yaml.Node
+}
+
+// validateWorkflow checks if the release workflow uploads files of each publisher to GitHub Actions Artifacts.
+func validateWorkflow(ps *problems, root *yaml.Node, publishers []string, artifacts *config.Artifacts) {
+	jobs := yamlnode.Get(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		ps.add(root, "jobs is required")
+		return
+	}
+	steps := uploadSteps(jobs)
+	for _, publisher := range publishers {
+		p := artifacts.Get(publisher)
+		step := findUploadStep(steps, p.Name)
+		if step == nil {
+			ps.add(jobs, "no step uploads the artifact %s with actions/upload-artifact for %s", p.Name, publisher)
+			continue
+		}
+		if step.path == nil {
+			ps.add(step.step, "with.path of the step uploading the artifact %s is required", p.Name)
+			continue
+		}
+		if !coversPath(strings.Split(step.path.Value, "\n"), p.Path) {
+			ps.add(step.path, "path of the artifact %s doesn't include %s for %s", p.Name, p.Path, publisher)
+		}
+	}
+}
+
+func uploadSteps(jobs *yaml.Node) []*uploadStep {
+	var steps []*uploadStep
+	for i := 1; i < len(jobs.Content); i += 2 {
+		seq := yamlnode.Get(jobs.Content[i], "steps")
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, step := range seq.Content {
+			uses := yamlnode.Get(step, "uses")
+			if uses == nil || !strings.HasPrefix(uses.Value, "actions/upload-artifact@") {
+				continue
+			}
+			with := yamlnode.Get(step, "with")
+			s := &uploadStep{
+				name: defaultUploadArtifactName,
+				step: step,
+				path: yamlnode.Get(with, "path"),
+			}
+			if name := yamlnode.Get(with, "name"); name != nil {
+				s.name = name.Value
+			}
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+func findUploadStep(steps []*uploadStep, name string) *uploadStep {
+	for _, step := range steps {
+		if step.name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// coversPath checks if paths of actions/upload-artifact include a path in the artifact.
+// actions/upload-artifact strips the least common ancestor of paths, so the path in the artifact is relative to it.
+// Files of publishers are in GoReleaser's dist directory, so the common ancestor must be dist.
+// Otherwise, for example, dist/homebrew is uploaded as the artifact root and <artifact>/homebrew doesn't exist.
+func coversPath(lines []string, p string) bool {
+	var paths []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, strings.TrimSuffix(path.Clean(line), "/"))
+	}
+	if len(paths) == 0 {
+		return false
+	}
+	if commonAncestor(paths) != distDir {
+		return false
+	}
+	target := path.Join(distDir, p)
+	for _, s := range paths {
+		if s == target || strings.HasPrefix(target, s+"/") || strings.HasPrefix(s, target+"/") {
+			return true
+		}
+		if matched, _ := path.Match(s, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// commonAncestor returns the least common ancestor directory of paths.
+// A path including glob characters or a file extension is regarded as a file, so its parent directory is used.
+func commonAncestor(paths []string) string {
+	var common []string
+	for i, p := range paths {
+		segs := strings.Split(searchDir(p), "/")
+		if i == 0 {
+			common = segs
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(segs) && common[n] == segs[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return strings.Join(common, "/")
+}
+
+func searchDir(p string) string {
+	if i := strings.IndexAny(p, "*?["); i != -1 {
+		return path.Dir(p[:i] + "x")
+	}
+	if path.Ext(p) != "" {
+		return path.Dir(p)
+	}
+	return p
+}
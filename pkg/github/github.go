@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/google/go-github/v90/github"
 	"golang.org/x/oauth2"
@@ -24,15 +26,25 @@ type (
 )
 
 func New(ctx context.Context) (*Client, error) {
-	client, err := github.NewClient(github.WithHTTPClient(getHTTPClientForGitHub(ctx, getGitHubToken())))
+	client, err := github.NewClient(github.WithHTTPClient(getHTTPClientForGitHub(ctx, getGitHubToken(ctx))))
 	if err != nil {
 		return nil, fmt.Errorf("create a GitHub client: %w", err)
 	}
 	return client, nil
 }
 
-func getGitHubToken() string {
-	return os.Getenv("GITHUB_TOKEN")
+func getGitHubToken(ctx context.Context) string {
+	for _, envName := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(envName); token != "" {
+			return token
+		}
+	}
+	// Fall back to the token of GitHub CLI if it's installed.
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
 }
 
 func getHTTPClientForGitHub(ctx context.Context, token string) *http.Client {
@@ -0,0 +1,60 @@
+// Package yamlnode edits YAML documents with keeping comments.
+package yamlnode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// Read reads a YAML file whose root is a mapping.
+func Read(fs afero.Fs, p string) (*yaml.Node, error) {
+	b, err := afero.ReadFile(fs, p)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", p, err)
+	}
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(b, node); err != nil {
+		return nil, fmt.Errorf("parse %s as YAML: %w", p, err)
+	}
+	if node.Kind != yaml.DocumentNode || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New(p + " must be a YAML mapping")
+	}
+	return node, nil
+}
+
+// Get returns the value of a key in a mapping node, or nil if the key isn't found.
+func Get(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Set sets the value of a key in a mapping node.
+// The comments of the existing value are kept.
+func Set(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			old := m.Content[i+1]
+			value.LineComment = old.LineComment
+			value.HeadComment = old.HeadComment
+			value.FootComment = old.FootComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, Str(key), value)
+}
+
+// Str returns a string scalar node.
+func Str(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}